@@ -3,11 +3,24 @@ package commands
 import (
 	"os"
 
+	"github.com/kubefill/kubefill/pkg/grpcauth"
 	"github.com/kubefill/kubefill/reposerver"
+	"github.com/kubefill/kubefill/util/env"
 	"github.com/spf13/cobra"
 )
 
 func NewCommand() *cobra.Command {
+	var (
+		tlsCert string
+		tlsKey  string
+		tlsCA   string
+		token   string
+
+		requireTLS        bool
+		allowLocalRemotes bool
+
+		manifestCacheSize int
+	)
 	var command = &cobra.Command{
 		Use:               "kubefill-reposerver",
 		Short:             "Run the kubefill repo server",
@@ -20,11 +33,31 @@ func NewCommand() *cobra.Command {
 				rootDir = ""
 			}
 
-			serverConfig := reposerver.ServerConfig{RootDir: rootDir}
+			serverConfig := reposerver.ServerConfig{
+				RootDir: rootDir,
+				Auth: grpcauth.Config{
+					CertFile:   tlsCert,
+					KeyFile:    tlsKey,
+					CAFile:     tlsCA,
+					Token:      token,
+					RequireTLS: requireTLS,
+				},
+				ManifestCacheSize: manifestCacheSize,
+				AllowLocalRemotes: allowLocalRemotes,
+			}
 			server := reposerver.NewServer(serverConfig)
 			server.Run()
 		},
 	}
 
+	command.Flags().StringVar(&tlsCert, "tls-cert", env.StringFromEnv("REPO_SERVER_TLS_CERT", ""), "TLS certificate file, enables TLS")
+	command.Flags().StringVar(&tlsKey, "tls-key", env.StringFromEnv("REPO_SERVER_TLS_KEY", ""), "TLS key file")
+	command.Flags().StringVar(&tlsCA, "tls-client-ca", env.StringFromEnv("REPO_SERVER_TLS_CLIENT_CA", ""), "CA file of client certificates, enables mutual TLS")
+	command.Flags().StringVar(&token, "token", env.StringFromEnv("REPO_SERVER_TOKEN", ""), "Service token required from clients")
+	command.Flags().BoolVar(&requireTLS, "require-tls", env.ParseBoolFromEnv("REPO_SERVER_REQUIRE_TLS", false), "Refuse to start with a service token but without TLS")
+
+	command.Flags().IntVar(&manifestCacheSize, "manifest-cache-size", env.ParseNumFromEnv("MANIFEST_CACHE_SIZE", reposerver.DEFAULT_MANIFEST_CACHE_SIZE, 0, 1000000), "Number of parsed application manifests cached, 0 disables the cache")
+	command.Flags().BoolVar(&allowLocalRemotes, "allow-local-remotes", env.ParseBoolFromEnv("ALLOW_LOCAL_REMOTES", false), "Accept file:// URLs and local paths as repo URLs")
+
 	return command
 }
@@ -1,7 +1,10 @@
 package commands
 
 import (
+	"time"
+
 	"github.com/kubefill/kubefill/common"
+	"github.com/kubefill/kubefill/pkg/grpcauth"
 	"github.com/kubefill/kubefill/server"
 	"github.com/kubefill/kubefill/util/env"
 	"github.com/spf13/cobra"
@@ -14,6 +17,16 @@ func NewCommand() *cobra.Command {
 		logsPath              string
 		kubeConfig            string
 		secretsKey            string
+		secretsKeys           string
+		secretsKeyId          string
+		syncWorkers           int
+		syncTimeout           time.Duration
+		pruneInterval         time.Duration
+		repoServerAuth        grpcauth.Config
+		smtpAddress           string
+		smtpFrom              string
+		smtpUsername          string
+		smtpPassword          string
 	)
 	var command = &cobra.Command{
 		Use:               "kubefill-server",
@@ -27,6 +40,16 @@ func NewCommand() *cobra.Command {
 				LogsPath:              logsPath,
 				KubeConfig:            kubeConfig,
 				SecretsKey:            secretsKey,
+				SecretsKeys:           secretsKeys,
+				SecretsKeyId:          secretsKeyId,
+				SyncWorkers:           syncWorkers,
+				SyncTimeout:           syncTimeout,
+				PruneInterval:         pruneInterval,
+				RepoServerAuth:        repoServerAuth,
+				SMTPAddress:           smtpAddress,
+				SMTPFrom:              smtpFrom,
+				SMTPUsername:          smtpUsername,
+				SMTPPassword:          smtpPassword,
 			}
 			server := server.NewServer(serverConfig)
 			server.Init()
@@ -38,7 +61,23 @@ func NewCommand() *cobra.Command {
 	command.Flags().StringVar(&postgresAddressNoPort, "postgres", env.StringFromEnv("KUBEFILL_POSTGRES", common.DefaultPostgresAddrNoPort), "PostgreSQL address")
 	command.Flags().StringVar(&logsPath, "logs-path", env.StringFromEnv("LOGS_PATH", common.DefaultLogsPath), "Logs path")
 	command.Flags().StringVar(&kubeConfig, "kubeconfig", env.StringFromEnv("KUBECONFIG", common.KubeConfig), "Kube config path")
-	command.Flags().StringVar(&secretsKey, "secrets-key", env.StringFromEnv("SECRETS_KEY", common.SecretsKey), "Secrets key")
+	command.Flags().StringVar(&secretsKey, "secrets-key", env.StringFromEnv("SECRETS_KEY", common.SecretsKey), "Secrets key, also decrypts secrets stored before key IDs")
+	command.Flags().StringVar(&secretsKeys, "secrets-keys", env.StringFromEnv("SECRETS_KEYS", ""), "Additional secrets keys as comma separated id=key pairs")
+	command.Flags().StringVar(&secretsKeyId, "secrets-key-id", env.StringFromEnv("SECRETS_KEY_ID", ""), "ID of the key new secrets are encrypted with, defaults to the secrets key, \""+server.DEFAULT_KEY_ID+"\", or else the first additional key")
+	command.Flags().IntVar(&syncWorkers, "sync-workers", env.ParseNumFromEnv("SYNC_WORKERS", common.DefaultSyncWorkers, 1, 64), "Number of repos synced concurrently")
+	command.Flags().DurationVar(&syncTimeout, "sync-timeout", env.ParseDurationFromEnv("SYNC_TIMEOUT", common.DefaultSyncTimeout, time.Second, time.Hour), "Timeout of a single background repo sync")
+	command.Flags().DurationVar(&pruneInterval, "prune-interval", env.ParseDurationFromEnv("PRUNE_INTERVAL", common.DefaultPruneInterval, 0, 7*24*time.Hour), "How often checkouts of deleted repos and branches are removed, 0 disables it")
+	command.Flags().BoolVar(&repoServerAuth.TLS, "repo-server-tls", env.ParseBoolFromEnv("REPO_SERVER_TLS", false), "Connect to the repo server over TLS")
+	command.Flags().StringVar(&repoServerAuth.CAFile, "repo-server-ca", env.StringFromEnv("REPO_SERVER_TLS_CA", ""), "CA file of the repo server certificate, enables TLS")
+	command.Flags().StringVar(&repoServerAuth.CertFile, "repo-server-client-cert", env.StringFromEnv("REPO_SERVER_TLS_CLIENT_CERT", ""), "Client certificate file for mutual TLS")
+	command.Flags().StringVar(&repoServerAuth.KeyFile, "repo-server-client-key", env.StringFromEnv("REPO_SERVER_TLS_CLIENT_KEY", ""), "Client key file for mutual TLS")
+	command.Flags().StringVar(&repoServerAuth.ServerName, "repo-server-name", env.StringFromEnv("REPO_SERVER_TLS_SERVER_NAME", ""), "Expected name in the repo server certificate, defaults to the address host")
+	command.Flags().StringVar(&repoServerAuth.Token, "repo-server-token", env.StringFromEnv("REPO_SERVER_TOKEN", ""), "Service token sent to the repo server")
+	command.Flags().BoolVar(&repoServerAuth.RequireTLS, "repo-server-require-tls", env.ParseBoolFromEnv("REPO_SERVER_REQUIRE_TLS", false), "Refuse to send the service token without TLS")
+	command.Flags().StringVar(&smtpAddress, "smtp-address", env.StringFromEnv("SMTP_ADDRESS", ""), "host:port of the SMTP server email notifications are sent through")
+	command.Flags().StringVar(&smtpFrom, "smtp-from", env.StringFromEnv("SMTP_FROM", "kubefill@localhost"), "Sender address of email notifications")
+	command.Flags().StringVar(&smtpUsername, "smtp-username", env.StringFromEnv("SMTP_USERNAME", ""), "SMTP username, none disables authentication")
+	command.Flags().StringVar(&smtpPassword, "smtp-password", env.StringFromEnv("SMTP_PASSWORD", ""), "SMTP password")
 
 	return command
 }
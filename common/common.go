@@ -1,9 +1,14 @@
 package common
 
+import "time"
+
 const (
 	DefaultRepoServerAddr     = "kubefill-repo-server:8081"
 	DefaultPostgresAddrNoPort = "kubefill-postgres"
 	KubeConfig                = ""
 	DefaultLogsPath           = ""
 	SecretsKey                = ""
+	DefaultSyncWorkers        = 4
+	DefaultSyncTimeout        = 5 * time.Minute
+	DefaultPruneInterval      = time.Hour
 )
@@ -1,7 +1,11 @@
 package application
 
 import (
+	"errors"
+	"fmt"
+
 	"github.com/kubefill/kubefill/pkg/db"
+	"github.com/kubefill/kubefill/pkg/manifest"
 )
 
 func NewService(db *db.Connection) *Service {
@@ -16,11 +20,23 @@ func (s *Service) List() []Application {
 	return applications
 }
 
+func (s *Service) ListByRepoId(repoId uint) ([]db.Application, error) {
+	var applications []db.Application
+	err := s.db.Where("repo_id = ?", repoId).Find(&applications).Error
+	return applications, err
+}
+
 func (s *Service) Create(payload Application) Application {
 	application := db.Application{
 		Name:         payload.Name,
 		RepoID:       payload.RepoID,
 		ManifestPath: payload.ManifestPath,
+		ManifestType: payload.ManifestType,
+		ChartPath:    payload.ChartPath,
+		Workload:     payload.Workload,
+		Discovered:   payload.Discovered,
+		GitOwned:     payload.GitOwned,
+		AppSettings:  payload.AppSettings,
 	}
 	s.db.Create(&application)
 	payload.Id = int(application.ID)
@@ -58,3 +74,42 @@ func (s *Service) Delete(application db.Application) error {
 
 	return nil
 }
+
+// ValidateManifestType defaults an empty manifest type to static and checks
+// that helm applications point at a chart.
+func ValidateManifestType(manifestType string, chartPath string) (string, error) {
+	switch manifestType {
+	case "", MANIFEST_TYPE_STATIC:
+		return MANIFEST_TYPE_STATIC, nil
+	case MANIFEST_TYPE_HELM:
+		if chartPath == "" {
+			return "", errors.New("chart_path is required for helm applications")
+		}
+
+		return MANIFEST_TYPE_HELM, nil
+	case MANIFEST_TYPE_KUSTOMIZE, MANIFEST_TYPE_TEMPLATE:
+		return manifestType, nil
+	default:
+		return "", fmt.Errorf("unknown manifest_type %q", manifestType)
+	}
+}
+
+// ManifestDir returns the repo relative directory the reposerver reads an
+// application's manifests from.
+func ManifestDir(app db.Application) string {
+	if app.ManifestType == MANIFEST_TYPE_HELM {
+		return app.ChartPath
+	}
+
+	return app.ManifestPath
+}
+
+// WorkloadRef returns the workload reference passed to the reposerver, empty
+// for applications running a batch/v1 Job.
+func WorkloadRef(app db.Application) string {
+	if app.Workload == nil {
+		return ""
+	}
+
+	return manifest.Workload(app.Workload.APIVersion, app.Workload.Kind)
+}
@@ -0,0 +1,169 @@
+package application
+
+import (
+	"fmt"
+	"net/mail"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/kubefill/kubefill/pkg/client"
+	"github.com/kubefill/kubefill/pkg/db"
+	"github.com/robfig/cron/v3"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// CONFIG_FILE declares the applications of a repo at its root.
+	CONFIG_FILE = ".kubefill.yaml"
+
+	NOTIFY_WEBHOOK = "webhook"
+	NOTIFY_SLACK   = "slack"
+	NOTIFY_EMAIL   = "email"
+)
+
+var jobPhases = []string{client.PHASE_PENDING, client.PHASE_RUNNING, client.PHASE_SUCCEEDED, client.PHASE_FAILED}
+
+// RepoConfig is the contents of a CONFIG_FILE.
+type RepoConfig struct {
+	Applications []AppConfig `json:"applications"`
+}
+
+// AppConfig declares an application. Name identifies it within the repo, so
+// renaming it replaces the application.
+type AppConfig struct {
+	Name          string                  `json:"name"`
+	Path          string                  `json:"path"`
+	Type          string                  `json:"type,omitempty"`
+	ChartPath     string                  `json:"chartPath,omitempty"`
+	Workload      *db.Workload            `json:"workload,omitempty"`
+	Concurrency   int                     `json:"concurrency,omitempty"`
+	Schedules     []string                `json:"schedules,omitempty"`
+	Secrets       []string                `json:"secrets,omitempty"`
+	Notifications []db.NotificationTarget `json:"notifications,omitempty"`
+}
+
+// ParseRepoConfig decodes and validates a CONFIG_FILE. It returns every
+// problem found rather than the first, unknown fields included.
+func ParseRepoConfig(contents []byte) (RepoConfig, []string) {
+	var config RepoConfig
+	err := yaml.UnmarshalStrict(contents, &config)
+
+	if err != nil {
+		return config, []string{fmt.Sprintf("%s: %v", CONFIG_FILE, err)}
+	}
+
+	var problems []string
+	names := make(map[string]bool)
+
+	for i := range config.Applications {
+		app := &config.Applications[i]
+		prefix := fmt.Sprintf("applications[%d]", i)
+
+		if app.Name != "" {
+			prefix = fmt.Sprintf("%s (%s)", prefix, app.Name)
+		}
+
+		if strings.TrimSpace(app.Name) == "" {
+			problems = append(problems, prefix+": name is required")
+		} else if names[app.Name] {
+			problems = append(problems, prefix+": duplicate name")
+		}
+
+		names[app.Name] = true
+
+		if strings.TrimSpace(app.Path) == "" {
+			problems = append(problems, prefix+": path is required")
+		} else if !inRepo(app.Path) || (app.ChartPath != "" && !inRepo(app.ChartPath)) {
+			problems = append(problems, prefix+": paths must stay inside the repo")
+		}
+
+		manifestType, err := ValidateManifestType(app.Type, app.ChartPath)
+
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %v", prefix, err))
+		}
+
+		app.Type = manifestType
+
+		for _, problem := range ValidateSettings(app.Settings()) {
+			problems = append(problems, fmt.Sprintf("%s: %s", prefix, problem))
+		}
+	}
+
+	return config, problems
+}
+
+func (c AppConfig) Settings() db.AppSettings {
+	return db.AppSettings{
+		Concurrency:     c.Concurrency,
+		Schedules:       c.Schedules,
+		RequiredSecrets: c.Secrets,
+		Notifications:   c.Notifications,
+	}
+}
+
+// ValidateSettings checks the run settings of an application: schedules are
+// standard five field cron expressions and notification targets have a
+// known type and a URL or address matching it.
+func ValidateSettings(settings db.AppSettings) []string {
+	var problems []string
+
+	if settings.Concurrency < 0 {
+		problems = append(problems, "concurrency must not be negative")
+	}
+
+	for _, schedule := range settings.Schedules {
+		if _, err := cron.ParseStandard(schedule); err != nil {
+			problems = append(problems, fmt.Sprintf("invalid schedule %q: %v", schedule, err))
+		}
+	}
+
+	for _, name := range settings.RequiredSecrets {
+		if strings.TrimSpace(name) == "" {
+			problems = append(problems, "secret names must not be empty")
+		}
+	}
+
+	for _, target := range settings.Notifications {
+		switch target.Type {
+		case NOTIFY_WEBHOOK, NOTIFY_SLACK:
+			u, err := url.Parse(target.Target)
+
+			if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+				problems = append(problems, fmt.Sprintf("%s notification target %q is not an http(s) URL", target.Type, target.Target))
+			}
+		case NOTIFY_EMAIL:
+			if _, err := mail.ParseAddress(target.Target); err != nil {
+				problems = append(problems, fmt.Sprintf("email notification target %q is not an address", target.Target))
+			}
+		default:
+			problems = append(problems, fmt.Sprintf("unknown notification type %q", target.Type))
+		}
+
+		for _, phase := range target.Phases {
+			if !contains(jobPhases, phase) {
+				problems = append(problems, fmt.Sprintf("unknown notification phase %q, expected one of %s", phase, strings.Join(jobPhases, ", ")))
+			}
+		}
+	}
+
+	return problems
+}
+
+// inRepo reports whether a repo relative path stays inside the repo, a
+// leading slash is read as the repo root.
+func inRepo(p string) bool {
+	cleaned := path.Clean(strings.TrimPrefix(p, "/"))
+	return cleaned != ".." && !strings.HasPrefix(cleaned, "../")
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
@@ -2,20 +2,43 @@ package application
 
 import "github.com/kubefill/kubefill/pkg/db"
 
+const (
+	MANIFEST_TYPE_STATIC    = "static"
+	MANIFEST_TYPE_HELM      = "helm"
+	MANIFEST_TYPE_KUSTOMIZE = "kustomize"
+	MANIFEST_TYPE_TEMPLATE  = "template"
+)
+
 type Application struct {
-	Id           int    `json:"id"`
-	Name         string `json:"name"`
-	RepoID       uint   `json:"repo_id"`
-	ManifestPath string `json:"manifest_path"`
-	Created_At   string `json:"created_at"`
-	Updated_At   string `json:"updated_at"`
-	Deleted_At   string `json:"deleted_at"`
+	Id           int          `json:"id"`
+	Name         string       `json:"name"`
+	RepoID       uint         `json:"repo_id"`
+	ManifestPath string       `json:"manifest_path"`
+	ManifestType string       `json:"manifest_type"`
+	ChartPath    string       `json:"chart_path"`
+	Workload     *db.Workload `gorm:"serializer:json" json:"workload"`
+	Discovered   bool         `json:"discovered"`
+	GitOwned     bool         `json:"git_owned"`
+	Created_At   string       `json:"created_at"`
+	Updated_At   string       `json:"updated_at"`
+	Deleted_At   string       `json:"deleted_at"`
+	db.AppSettings
 }
 
 type ApplicationUpdate struct {
-	Name         string `json:"name"`
-	RepoID       uint   `json:"repo_id"`
-	ManifestPath string `json:"manifest_path"`
+	Name         string       `json:"name"`
+	RepoID       uint         `json:"repo_id"`
+	ManifestPath string       `json:"manifest_path"`
+	ManifestType string       `json:"manifest_type"`
+	ChartPath    string       `json:"chart_path"`
+	Workload     *db.Workload `json:"workload"`
+	db.AppSettings
+}
+
+type DiscoverRequest struct {
+	Paths []string `json:"paths"`
+	// AutoDiscover is left as it is when omitted.
+	AutoDiscover *bool `json:"auto_discover"`
 }
 
 type Service struct {
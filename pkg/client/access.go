@@ -0,0 +1,61 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/kubefill/kubefill/pkg/db"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// workloadVerbs are the verbs kubefill needs on a workload kind in every
+// namespace: runs create and delete objects, the informer lists and watches
+// them.
+var workloadVerbs = []string{"get", "list", "watch", "create", "delete"}
+
+// CheckWorkloadAccess asks the API server whether kubefill's service account
+// may run and follow a workload kind, and names the RBAC rule to add when it
+// may not. Jobs, the default, are covered by the install manifest.
+func (c *Clientset) CheckWorkloadAccess(ctx context.Context, workload *db.Workload) error {
+	if workload == nil {
+		return nil
+	}
+
+	gvr, err := c.WorkloadResource(workload.APIVersion, workload.Kind)
+
+	if err != nil {
+		return err
+	}
+
+	var denied []string
+
+	for _, verb := range workloadVerbs {
+		review := &authorizationv1.SelfSubjectAccessReview{
+			Spec: authorizationv1.SelfSubjectAccessReviewSpec{
+				ResourceAttributes: &authorizationv1.ResourceAttributes{
+					Group:    gvr.Group,
+					Version:  gvr.Version,
+					Resource: gvr.Resource,
+					Verb:     verb,
+				},
+			},
+		}
+		resp, err := c.AuthorizationV1().SelfSubjectAccessReviews().Create(ctx, review, metav1.CreateOptions{})
+
+		if err != nil {
+			return fmt.Errorf("could not check access to %s: %w", gvr.String(), err)
+		}
+
+		if !resp.Status.Allowed {
+			denied = append(denied, verb)
+		}
+	}
+
+	if len(denied) > 0 {
+		return fmt.Errorf("kubefill is not allowed to %s %s, add them to the kubefill-server-role ClusterRole: apiGroups [%q], resources [%q]", strings.Join(denied, ", "), gvr.Resource, gvr.Group, gvr.Resource)
+	}
+
+	return nil
+}
@@ -4,22 +4,43 @@ import (
 	"os"
 
 	log "github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/restmapper"
 	"k8s.io/client-go/tools/clientcmd"
 )
 
 type Clientset struct {
 	*kubernetes.Clientset
+	dynamic dynamic.Interface
+	mapper  meta.RESTMapper
 }
 
 func NewClientset() *Clientset {
+	clusterConfig := connect()
+	clientset, err := kubernetes.NewForConfig(clusterConfig)
+
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	dynamicClient, err := dynamic.NewForConfig(clusterConfig)
+
+	if err != nil {
+		log.Fatalln(err)
+	}
+
 	return &Clientset{
-		connect(),
+		Clientset: clientset,
+		dynamic:   dynamicClient,
+		mapper:    restmapper.NewDeferredDiscoveryRESTMapper(memory.NewMemCacheClient(clientset.Discovery())),
 	}
 }
 
-func connect() *kubernetes.Clientset {
+func connect() *rest.Config {
 	kubeConfig := os.Getenv("KUBECONFIG")
 	var clusterConfig *rest.Config
 	var err error
@@ -34,11 +55,5 @@ func connect() *kubernetes.Clientset {
 		log.Fatalln(err)
 	}
 
-	clientset, err := kubernetes.NewForConfig(clusterConfig)
-
-	if err != nil {
-		log.Fatalln(err)
-	}
-
-	return clientset
+	return clusterConfig
 }
@@ -13,7 +13,11 @@ import (
 	"github.com/kubefill/kubefill/pkg/job"
 	log "github.com/sirupsen/logrus"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic/dynamicinformer"
 
 	"k8s.io/client-go/informers"
 	"k8s.io/client-go/tools/cache"
@@ -65,11 +69,10 @@ func (c *PodLoggingController) streamPodLogs(ctx context.Context, jobId uint, po
 }
 
 func (c *PodLoggingController) updateJobStatus(id uint, phase string) {
-	job, err := c.jobService.Get(id)
+	err := c.jobService.SetPhase(id, phase)
 
-	if err == nil {
-		job.Phase = phase
-		c.jobService.Update(job)
+	if err != nil {
+		log.Errorf("failed to set the phase of job %d: %v", id, err)
 	}
 }
 
@@ -78,7 +81,11 @@ func (c *PodLoggingController) podAdd(obj interface{}) {
 	labels := pod.ObjectMeta.Labels
 	job_id := JobIdAsUint(labels["job_id"])
 	log.Infof("pod added for job id %d with phase %s", job_id, string(pod.Status.Phase))
-	c.updateJobStatus(job_id, string(pod.Status.Phase))
+
+	// The phase of other workload kinds comes from their own status.
+	if _, ok := labels[WORKLOAD_LABEL]; !ok {
+		c.updateJobStatus(job_id, string(pod.Status.Phase))
+	}
 }
 
 func saveLog(jobId uint, podName string, logMessage string, logsPath string) {
@@ -106,7 +113,11 @@ func (c *PodLoggingController) podUpdate(old, new interface{}) {
 	labels := pod.ObjectMeta.Labels
 	job_id := JobIdAsUint(labels["job_id"])
 	log.Infof("pod %s updated, job id %d, phase %s", job_id, pod.Name, string(pod.Status.Phase))
-	c.updateJobStatus(job_id, string(pod.Status.Phase))
+
+	if _, ok := labels[WORKLOAD_LABEL]; !ok {
+		c.updateJobStatus(job_id, string(pod.Status.Phase))
+	}
+
 	_, ok := c.pods[pod.Name]
 
 	if string(pod.Status.Phase) == "Running" {
@@ -179,6 +190,102 @@ func NewInformer(clientset *Clientset, jobService *job.JobService) *Informer {
 	return &Informer{
 		clientset:  clientset,
 		jobService: jobService,
+		stop:       make(chan struct{}),
+		watched:    map[schema.GroupVersionResource]chan struct{}{},
+	}
+}
+
+// WatchWorkload starts following the phase of the objects of a workload kind
+// created by kubefill. Kinds already watched are ignored. A kind kubefill may
+// not list or watch stops being watched, it is tried again on the next call.
+func (s *Informer) WatchWorkload(apiVersion string, kind string) error {
+	gvr, err := s.clientset.WorkloadResource(apiVersion, kind)
+
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.watched[gvr]; ok {
+		return nil
+	}
+
+	stop := make(chan struct{})
+	s.watched[gvr] = stop
+	informer := dynamicinformer.NewFilteredDynamicInformer(
+		s.clientset.dynamic,
+		gvr,
+		metav1.NamespaceAll,
+		3*time.Minute,
+		cache.Indexers{},
+		func(opts *metav1.ListOptions) {
+			opts.LabelSelector = "invoked="
+		}).Informer()
+
+	informer.AddEventHandler(
+		cache.ResourceEventHandlerFuncs{
+			AddFunc: s.workloadUpdate,
+			UpdateFunc: func(old, new interface{}) {
+				s.workloadUpdate(new)
+			},
+		},
+	)
+
+	err = informer.SetWatchErrorHandler(func(r *cache.Reflector, err error) {
+		if !apierrors.IsForbidden(err) {
+			cache.DefaultWatchErrorHandler(r, err)
+			return
+		}
+
+		log.Errorf("stopped watching %s workloads: %v", gvr.String(), err)
+		s.unwatch(gvr, stop)
+	})
+
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		select {
+		case <-s.stop:
+			s.unwatch(gvr, stop)
+		case <-stop:
+		}
+	}()
+
+	go informer.Run(stop)
+	log.Infof("watching %s workloads", gvr.String())
+	return nil
+}
+
+// unwatch stops the informer of a workload kind, unless it was replaced
+// meanwhile.
+func (s *Informer) unwatch(gvr schema.GroupVersionResource, stop chan struct{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.watched[gvr] == stop {
+		delete(s.watched, gvr)
+		close(stop)
+	}
+}
+
+func (s *Informer) workloadUpdate(obj interface{}) {
+	u, ok := obj.(*unstructured.Unstructured)
+
+	if !ok {
+		return
+	}
+
+	jobId := JobIdAsUint(u.GetLabels()["job_id"])
+	phase := WorkloadPhase(u)
+	log.Infof("%s %s updated, job id %d, phase %s", u.GetKind(), u.GetName(), jobId, phase)
+	err := s.jobService.SetPhase(jobId, phase)
+
+	if err != nil {
+		log.Errorf("failed to set the phase of job %d: %v", jobId, err)
 	}
 }
 
@@ -198,11 +305,9 @@ func (s *Informer) StartInformer(logsPath string) {
 		informers.WithNamespace(""),
 		labelOptions)
 	controller := NewPodLoggingController(factory, s.jobService, s.clientset, logsPath)
-	stop := make(chan struct{})
-
-	defer close(stop)
+	defer close(s.stop)
 
-	err := controller.Run(stop)
+	err := controller.Run(s.stop)
 	if err != nil {
 		log.Fatalln(err)
 	}
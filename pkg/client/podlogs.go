@@ -63,11 +63,18 @@ func (c *Clientset) StreamPodLogs(ctx context.Context, lines chan string, stop c
 	}
 }
 
-func (c *Clientset) Run(jobName string, jobConfig JobConfig) (*batchv1.Job, error) {
-	jobs := c.BatchV1().Jobs(jobConfig.ObjectMeta.Namespace)
+// Run creates the Job and any supporting objects of jobConfig and returns
+// references to the objects it created next to the Job.
+func (c *Clientset) Run(jobName string, jobConfig JobConfig) (*batchv1.Job, []ObjectRef, error) {
 	jobSpec := genereateJobSpec(jobName, jobConfig)
+
+	if len(jobConfig.Resources) > 0 {
+		return c.runWithResources(context.TODO(), jobSpec, jobConfig.Resources)
+	}
+
+	jobs := c.BatchV1().Jobs(jobConfig.ObjectMeta.Namespace)
 	resp, err := jobs.Create(context.TODO(), jobSpec, metav1.CreateOptions{})
-	return resp, err
+	return resp, nil, err
 }
 
 func (c *Clientset) GetJobStatus(jobName string, namespace string) (*batchv1.JobStatus, error) {
@@ -0,0 +1,193 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	log "github.com/sirupsen/logrus"
+	"helm.sh/helm/v3/pkg/releaseutil"
+	batchv1 "k8s.io/api/batch/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+type resource struct {
+	object  *unstructured.Unstructured
+	mapping *meta.RESTMapping
+}
+
+// prepareResources resolves every supporting object to its API resource and
+// sorts them so that dependencies like ServiceAccounts and ConfigMaps come
+// first. Objects must live in the workload namespace since owner references do
+// not cross namespaces.
+func (c *Clientset) prepareResources(namespace string, objects []map[string]interface{}) ([]resource, error) {
+	var resources []resource
+
+	for _, object := range objects {
+		u := &unstructured.Unstructured{Object: object}
+		gvk := u.GroupVersionKind()
+		mapping, err := c.mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+
+		if err != nil {
+			return nil, fmt.Errorf("%s %s: %w", gvk.Kind, u.GetName(), err)
+		}
+
+		if mapping.Scope.Name() != meta.RESTScopeNameNamespace {
+			return nil, fmt.Errorf("%s %s: cluster scoped objects can not be owned by a workload", gvk.Kind, u.GetName())
+		}
+
+		if u.GetNamespace() != "" && u.GetNamespace() != namespace {
+			return nil, fmt.Errorf("%s %s: namespace %s does not match the workload namespace %s", gvk.Kind, u.GetName(), u.GetNamespace(), namespace)
+		}
+
+		u.SetNamespace(namespace)
+		resources = append(resources, resource{object: u, mapping: mapping})
+	}
+
+	sort.SliceStable(resources, func(i, j int) bool {
+		return installOrder(resources[i].object.GetKind()) < installOrder(resources[j].object.GetKind())
+	})
+
+	return resources, nil
+}
+
+func installOrder(kind string) int {
+	for i, k := range releaseutil.InstallOrder {
+		if k == kind {
+			return i
+		}
+	}
+
+	return len(releaseutil.InstallOrder)
+}
+
+// createResources creates the supporting objects of a workload, each owned by
+// it so they are garbage collected together. An owner without a UID, of a
+// workload which does not exist yet, is set by adoptResources later.
+func (c *Clientset) createResources(ctx context.Context, owner metav1.OwnerReference, namespace string, jobId string, resources []resource) ([]ObjectRef, error) {
+	var created []ObjectRef
+
+	for _, r := range resources {
+		if owner.UID != "" {
+			r.object.SetOwnerReferences(append(r.object.GetOwnerReferences(), owner))
+		}
+
+		labels := r.object.GetLabels()
+
+		if labels == nil {
+			labels = make(map[string]string)
+		}
+
+		labels["job_id"] = jobId
+		r.object.SetLabels(labels)
+
+		resp, err := c.dynamic.Resource(r.mapping.Resource).Namespace(namespace).Create(ctx, r.object, metav1.CreateOptions{})
+
+		if err != nil {
+			return created, fmt.Errorf("failed to create %s %s: %w", r.object.GetKind(), r.object.GetName(), err)
+		}
+
+		log.Infof("created %s %s/%s for %s %s", resp.GetKind(), resp.GetNamespace(), resp.GetName(), owner.Kind, owner.Name)
+		created = append(created, ObjectRef{
+			APIVersion: resp.GetAPIVersion(),
+			Kind:       resp.GetKind(),
+			Namespace:  resp.GetNamespace(),
+			Name:       resp.GetName(),
+			UID:        string(resp.GetUID()),
+		})
+	}
+
+	return created, nil
+}
+
+// adoptResources makes owner the owner of the supporting objects created
+// for it before it existed. created holds the objects in the order of
+// resources.
+func (c *Clientset) adoptResources(ctx context.Context, owner metav1.OwnerReference, namespace string, resources []resource, created []ObjectRef) error {
+	for i, ref := range created {
+		ownerReferences := append(resources[i].object.GetOwnerReferences(), owner)
+		patch, err := json.Marshal(map[string]interface{}{
+			"metadata": map[string]interface{}{"ownerReferences": ownerReferences},
+		})
+
+		if err != nil {
+			return err
+		}
+
+		_, err = c.dynamic.Resource(resources[i].mapping.Resource).Namespace(namespace).Patch(ctx, ref.Name, types.MergePatchType, patch, metav1.PatchOptions{})
+
+		if err != nil {
+			return fmt.Errorf("failed to set the owner of %s %s: %w", ref.Kind, ref.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// deleteResources deletes the supporting objects created for a workload
+// which could not be started. created holds the objects in the order of
+// resources.
+func (c *Clientset) deleteResources(ctx context.Context, namespace string, resources []resource, created []ObjectRef) {
+	propagation := metav1.DeletePropagationBackground
+
+	for i, ref := range created {
+		err := c.dynamic.Resource(resources[i].mapping.Resource).Namespace(namespace).Delete(ctx, ref.Name, metav1.DeleteOptions{PropagationPolicy: &propagation})
+
+		if err != nil && !errors.IsNotFound(err) {
+			log.Errorf("failed to delete %s %s: %v", ref.Kind, ref.Name, err)
+		}
+	}
+}
+
+// runWithResources creates the Job suspended, then its supporting objects,
+// and resumes it once they exist. If an object can not be created the Job is
+// deleted, which also removes the objects created so far.
+func (c *Clientset) runWithResources(ctx context.Context, jobSpec *batchv1.Job, objects []map[string]interface{}) (*batchv1.Job, []ObjectRef, error) {
+	jobs := c.BatchV1().Jobs(jobSpec.Namespace)
+	resources, err := c.prepareResources(jobSpec.Namespace, objects)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	suspend := jobSpec.Spec.Suspend
+	suspended := true
+	jobSpec.Spec.Suspend = &suspended
+	job, err := jobs.Create(ctx, jobSpec, metav1.CreateOptions{})
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	owner := metav1.OwnerReference{
+		APIVersion: batchv1.SchemeGroupVersion.String(),
+		Kind:       "Job",
+		Name:       job.Name,
+		UID:        job.UID,
+	}
+	created, err := c.createResources(ctx, owner, job.Namespace, job.Spec.Template.Labels["job_id"], resources)
+
+	if err != nil {
+		propagation := metav1.DeletePropagationBackground
+		deleteErr := jobs.Delete(ctx, job.Name, metav1.DeleteOptions{PropagationPolicy: &propagation})
+
+		if deleteErr != nil {
+			log.Errorf("failed to delete job %s: %v", job.Name, deleteErr)
+		}
+
+		return nil, created, err
+	}
+
+	if suspend != nil && *suspend {
+		return job, created, nil
+	}
+
+	patch := []byte(`{"spec":{"suspend":false}}`)
+	job, err = jobs.Patch(ctx, job.Name, types.MergePatchType, patch, metav1.PatchOptions{})
+	return job, created, err
+}
@@ -0,0 +1,267 @@
+package client
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/kubefill/kubefill/pkg/db"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// SECRET_ENV_PREFIX prefixes the env vars holding secrets referenced inside
+// a longer env value, command or argument.
+const SECRET_ENV_PREFIX = "KUBEFILL_SECRET_"
+
+// SecretRef matches a {{secrets.NAME}} reference to an application secret.
+var SecretRef = regexp.MustCompile(`{{\s*secrets\.([^}\s]+)\s*}}`)
+
+// Secret keys allow the same characters as file names of secret volumes.
+var secretKeyPattern = regexp.MustCompile(`^[-._a-zA-Z0-9]+$`)
+
+var envNameInvalid = regexp.MustCompile(`[^A-Z0-9_]`)
+
+// SecretRefs returns the names of the secrets referenced anywhere in value,
+// sorted and without duplicates.
+func SecretRefs(value interface{}) []string {
+	seen := make(map[string]bool)
+	walkStrings(value, func(s string) {
+		for _, match := range SecretRef.FindAllStringSubmatch(s, -1) {
+			seen[match[1]] = true
+		}
+	})
+
+	names := make([]string, 0, len(seen))
+
+	for name := range seen {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+	return names
+}
+
+func walkStrings(value interface{}, fn func(string)) {
+	switch v := value.(type) {
+	case string:
+		fn(v)
+	case map[string]interface{}:
+		for _, item := range v {
+			walkStrings(item, fn)
+		}
+	case []interface{}:
+		for _, item := range v {
+			walkStrings(item, fn)
+		}
+	}
+}
+
+// InjectSecrets rewrites the secret references in the pod template of a
+// workload to read the Secret secretName, which it returns holding the
+// referenced secrets, or nil when there are none. An env value which is a
+// reference becomes a secretKeyRef, references inside an env value, command
+// or argument go through a SECRET_ENV_PREFIX env var, and a secret volume
+// named after a reference mounts that secret as a file of its name. The
+// workload keeps no secret value, references anywhere else are an error.
+func InjectSecrets(object map[string]interface{}, workload *db.Workload, secretName string, secrets map[string]string) (map[string]interface{}, error) {
+	names := SecretRefs(object)
+
+	if len(names) == 0 {
+		return nil, nil
+	}
+
+	// Embedded references go through env vars, whose names must not clash.
+	envNames := make(map[string]string, len(names))
+
+	for _, name := range names {
+		if !secretKeyPattern.MatchString(name) {
+			return nil, fmt.Errorf("secret %s can not be injected, use letters, digits, '.', '_' and '-' in its name", name)
+		}
+
+		envName := secretEnvName(name)
+
+		if other, ok := envNames[envName]; ok {
+			return nil, fmt.Errorf("secrets %s and %s can not both be referenced, they share the env var %s", other, name, envName)
+		}
+
+		envNames[envName] = name
+	}
+
+	path := "spec.template"
+
+	if workload != nil {
+		path = podTemplatePath(workload)
+	}
+
+	var fields []string
+
+	if path != "" {
+		fields = strings.Split(path, ".")
+	}
+
+	podSpec, found, err := unstructured.NestedFieldNoCopy(object, append(fields, "spec")...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if spec, ok := podSpec.(map[string]interface{}); found && ok {
+		for _, key := range []string{"initContainers", "containers"} {
+			containers, _ := spec[key].([]interface{})
+
+			for _, container := range containers {
+				if c, ok := container.(map[string]interface{}); ok {
+					injectContainer(c, secretName)
+				}
+			}
+		}
+
+		volumes, _ := spec["volumes"].([]interface{})
+
+		for _, volume := range volumes {
+			if v, ok := volume.(map[string]interface{}); ok {
+				injectVolume(v, secretName)
+			}
+		}
+	}
+
+	if left := SecretRefs(object); len(left) > 0 {
+		return nil, fmt.Errorf("secrets %s are referenced outside of the env, command, args and secret volumes of the pod template", strings.Join(left, ", "))
+	}
+
+	data := make(map[string]interface{}, len(names))
+
+	for _, name := range names {
+		data[name] = secrets[name]
+	}
+
+	return map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Secret",
+		"type":       "Opaque",
+		"metadata":   map[string]interface{}{"name": secretName},
+		"stringData": data,
+	}, nil
+}
+
+// wholeSecretRef returns the secret s consists of, if it is a single
+// reference.
+func wholeSecretRef(s string) (string, bool) {
+	match := SecretRef.FindStringSubmatchIndex(s)
+
+	if match == nil || match[0] != 0 || match[1] != len(s) {
+		return "", false
+	}
+
+	return s[match[2]:match[3]], true
+}
+
+func secretEnvName(name string) string {
+	return SECRET_ENV_PREFIX + envNameInvalid.ReplaceAllString(strings.ToUpper(name), "_")
+}
+
+func secretKeyRef(secretName string, name string) map[string]interface{} {
+	return map[string]interface{}{
+		"secretKeyRef": map[string]interface{}{"name": secretName, "key": name},
+	}
+}
+
+// injectContainer rewrites the references of a container. Kubernetes only
+// expands $(VAR) to env vars defined before, so the env vars of embedded
+// references go first.
+func injectContainer(container map[string]interface{}, secretName string) {
+	embedded := make(map[string]bool)
+	expand := func(s string) string {
+		return SecretRef.ReplaceAllStringFunc(s, func(ref string) string {
+			name := SecretRef.FindStringSubmatch(ref)[1]
+			embedded[name] = true
+			return "$(" + secretEnvName(name) + ")"
+		})
+	}
+
+	env, _ := container["env"].([]interface{})
+	defined := make(map[string]bool)
+
+	for _, item := range env {
+		entry, ok := item.(map[string]interface{})
+
+		if !ok {
+			continue
+		}
+
+		if envName, ok := entry["name"].(string); ok {
+			defined[envName] = true
+		}
+
+		value, ok := entry["value"].(string)
+
+		if !ok {
+			continue
+		}
+
+		if name, ok := wholeSecretRef(value); ok {
+			delete(entry, "value")
+			entry["valueFrom"] = secretKeyRef(secretName, name)
+		} else {
+			entry["value"] = expand(value)
+		}
+	}
+
+	for _, key := range []string{"command", "args"} {
+		items, _ := container[key].([]interface{})
+
+		for i, item := range items {
+			if s, ok := item.(string); ok {
+				items[i] = expand(s)
+			}
+		}
+	}
+
+	var names []string
+
+	for name := range embedded {
+		if !defined[secretEnvName(name)] {
+			names = append(names, name)
+		}
+	}
+
+	if len(names) == 0 {
+		return
+	}
+
+	sort.Strings(names)
+	prepended := make([]interface{}, 0, len(names)+len(env))
+
+	for _, name := range names {
+		prepended = append(prepended, map[string]interface{}{
+			"name":      secretEnvName(name),
+			"valueFrom": secretKeyRef(secretName, name),
+		})
+	}
+
+	container["env"] = append(prepended, env...)
+}
+
+// injectVolume points a secret volume named after a reference at the run
+// Secret, mounting that secret alone unless items are given.
+func injectVolume(volume map[string]interface{}, secretName string) {
+	source, ok := volume["secret"].(map[string]interface{})
+
+	if !ok {
+		return
+	}
+
+	ref, _ := source["secretName"].(string)
+	name, ok := wholeSecretRef(ref)
+
+	if !ok {
+		return
+	}
+
+	source["secretName"] = secretName
+
+	if _, ok := source["items"]; !ok {
+		source["items"] = []interface{}{map[string]interface{}{"key": name, "path": name}}
+	}
+}
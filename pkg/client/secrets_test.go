@@ -0,0 +1,286 @@
+package client
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/kubefill/kubefill/pkg/db"
+	"sigs.k8s.io/yaml"
+)
+
+func object(t *testing.T, manifest string) map[string]interface{} {
+	var o map[string]interface{}
+
+	if err := yaml.Unmarshal([]byte(manifest), &o); err != nil {
+		t.Fatal(err)
+	}
+
+	return o
+}
+
+func TestSecretRefs(t *testing.T) {
+	value := []interface{}{
+		"{{secrets.b}} and {{ secrets.a }}",
+		map[string]interface{}{"x": "{{secrets.b}}", "y": []interface{}{"{{secrets.c.d}}", 1}},
+		"{{ secret.e }} {{secrets}}",
+	}
+
+	expected := []string{"a", "b", "c.d"}
+
+	if refs := SecretRefs(value); !reflect.DeepEqual(refs, expected) {
+		t.Fatalf("expected %v, got %v", expected, refs)
+	}
+}
+
+func TestInjectSecrets(t *testing.T) {
+	secrets := map[string]string{"token": "t0k3n", "db.password": "p4ss", "cert": "-----BEGIN-----"}
+
+	tests := []struct {
+		name     string
+		workload *db.Workload
+		object   string
+		expected string
+		data     map[string]interface{}
+		err      string
+	}{
+		{
+			name: "no references",
+			object: `
+spec:
+  template:
+    spec:
+      containers: [{name: main, env: [{name: A, value: a}]}]
+`,
+			expected: `
+spec:
+  template:
+    spec:
+      containers: [{name: main, env: [{name: A, value: a}]}]
+`,
+		},
+		{
+			name: "whole env value",
+			object: `
+spec:
+  template:
+    spec:
+      containers:
+        - name: main
+          env: [{name: TOKEN, value: "{{secrets.token}}"}]
+`,
+			expected: `
+spec:
+  template:
+    spec:
+      containers:
+        - name: main
+          env: [{name: TOKEN, valueFrom: {secretKeyRef: {name: run-secrets, key: token}}}]
+`,
+			data: map[string]interface{}{"token": "t0k3n"},
+		},
+		{
+			name: "embedded references",
+			object: `
+spec:
+  template:
+    spec:
+      initContainers:
+        - name: init
+          command: [sh, -c, "login --token {{secrets.token}}"]
+      containers:
+        - name: main
+          args: ["--password={{ secrets.db.password }}"]
+          env: [{name: URL, value: "postgres://user:{{secrets.db.password}}@db"}]
+`,
+			expected: `
+spec:
+  template:
+    spec:
+      initContainers:
+        - name: init
+          command: [sh, -c, "login --token $(KUBEFILL_SECRET_TOKEN)"]
+          env: [{name: KUBEFILL_SECRET_TOKEN, valueFrom: {secretKeyRef: {name: run-secrets, key: token}}}]
+      containers:
+        - name: main
+          args: ["--password=$(KUBEFILL_SECRET_DB_PASSWORD)"]
+          env:
+            - {name: KUBEFILL_SECRET_DB_PASSWORD, valueFrom: {secretKeyRef: {name: run-secrets, key: db.password}}}
+            - {name: URL, value: "postgres://user:$(KUBEFILL_SECRET_DB_PASSWORD)@db"}
+`,
+			data: map[string]interface{}{"token": "t0k3n", "db.password": "p4ss"},
+		},
+		{
+			name: "env var defined already",
+			object: `
+spec:
+  template:
+    spec:
+      containers:
+        - name: main
+          args: ["{{secrets.token}}"]
+          env: [{name: KUBEFILL_SECRET_TOKEN, value: "{{secrets.token}}"}]
+`,
+			expected: `
+spec:
+  template:
+    spec:
+      containers:
+        - name: main
+          args: ["$(KUBEFILL_SECRET_TOKEN)"]
+          env: [{name: KUBEFILL_SECRET_TOKEN, valueFrom: {secretKeyRef: {name: run-secrets, key: token}}}]
+`,
+			data: map[string]interface{}{"token": "t0k3n"},
+		},
+		{
+			name: "secret volume",
+			object: `
+spec:
+  template:
+    spec:
+      containers: [{name: main}]
+      volumes:
+        - {name: cert, secret: {secretName: "{{secrets.cert}}"}}
+        - {name: other, secret: {secretName: other}}
+`,
+			expected: `
+spec:
+  template:
+    spec:
+      containers: [{name: main}]
+      volumes:
+        - {name: cert, secret: {secretName: run-secrets, items: [{key: cert, path: cert}]}}
+        - {name: other, secret: {secretName: other}}
+`,
+			data: map[string]interface{}{"cert": "-----BEGIN-----"},
+		},
+		{
+			name:     "workload pod template path",
+			workload: &db.Workload{APIVersion: "batch/v1", Kind: "CronJob", PodTemplatePath: "spec.jobTemplate.spec.template"},
+			object: `
+spec:
+  jobTemplate:
+    spec:
+      template:
+        spec:
+          containers: [{name: main, env: [{name: TOKEN, value: "{{secrets.token}}"}]}]
+`,
+			expected: `
+spec:
+  jobTemplate:
+    spec:
+      template:
+        spec:
+          containers: [{name: main, env: [{name: TOKEN, valueFrom: {secretKeyRef: {name: run-secrets, key: token}}}]}]
+`,
+			data: map[string]interface{}{"token": "t0k3n"},
+		},
+		{
+			name: "unset secret",
+			object: `
+spec:
+  template:
+    spec:
+      containers: [{name: main, env: [{name: MISSING, value: "{{secrets.missing}}"}]}]
+`,
+			expected: `
+spec:
+  template:
+    spec:
+      containers: [{name: main, env: [{name: MISSING, valueFrom: {secretKeyRef: {name: run-secrets, key: missing}}}]}]
+`,
+			data: map[string]interface{}{"missing": ""},
+		},
+		{
+			name: "reference outside of the pod template",
+			object: `
+metadata:
+  annotations: {token: "{{secrets.token}}"}
+spec:
+  template:
+    spec:
+      containers: [{name: main}]
+`,
+			err: "referenced outside",
+		},
+		{
+			name: "reference in the image",
+			object: `
+spec:
+  template:
+    spec:
+      containers: [{name: main, image: "{{secrets.token}}"}]
+`,
+			err: "referenced outside",
+		},
+		{
+			name: "invalid name",
+			object: `
+spec:
+  template:
+    spec:
+      containers: [{name: main, env: [{name: A, value: "{{secrets.a/b}}"}]}]
+`,
+			err: "can not be injected",
+		},
+		{
+			name: "names sharing an env var",
+			object: `
+spec:
+  template:
+    spec:
+      containers: [{name: main, args: ["{{secrets.a.b}}", "{{secrets.a_b}}"]}]
+`,
+			err: "share the env var KUBEFILL_SECRET_A_B",
+		},
+		{
+			name: "names differing in case",
+			object: `
+spec:
+  template:
+    spec:
+      containers: [{name: main, args: ["{{secrets.token}}", "{{secrets.TOKEN}}"]}]
+`,
+			err: "share the env var KUBEFILL_SECRET_TOKEN",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			o := object(t, test.object)
+			secret, err := InjectSecrets(o, test.workload, "run-secrets", secrets)
+
+			if test.err != "" {
+				if err == nil || !strings.Contains(err.Error(), test.err) {
+					t.Fatalf("expected an error containing %q, got %v", test.err, err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if expected := object(t, test.expected); !reflect.DeepEqual(o, expected) {
+				t.Errorf("expected\n%v\ngot\n%v", expected, o)
+			}
+
+			if test.data == nil {
+				if secret != nil {
+					t.Fatalf("expected no Secret, got %v", secret)
+				}
+
+				return
+			}
+
+			if secret["kind"] != "Secret" || secret["metadata"].(map[string]interface{})["name"] != "run-secrets" {
+				t.Errorf("expected the Secret run-secrets, got %v", secret)
+			}
+
+			if data := secret["stringData"]; !reflect.DeepEqual(data, test.data) {
+				t.Errorf("expected stringData %v, got %v", test.data, data)
+			}
+		})
+	}
+}
@@ -1,10 +1,13 @@
 package client
 
 import (
+	"sync"
+
 	"github.com/kubefill/kubefill/pkg/job"
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/informers"
 	coreinformers "k8s.io/client-go/informers/core/v1"
 )
@@ -12,6 +15,10 @@ import (
 type Informer struct {
 	clientset  *Clientset
 	jobService *job.JobService
+	stop       chan struct{}
+	mu         sync.Mutex
+	// watched holds the stop channel of the informer of each workload kind.
+	watched map[schema.GroupVersionResource]chan struct{}
 }
 
 type PodLoggingController struct {
@@ -24,7 +31,17 @@ type PodLoggingController struct {
 }
 
 type JobConfig struct {
-	ObjectMeta metav1.ObjectMeta `json:"metadata"`
-	Spec       batchv1.JobSpec   `json:"spec"`
-	Labels     map[string]string `json:"labels"`
+	ObjectMeta metav1.ObjectMeta        `json:"metadata"`
+	Spec       batchv1.JobSpec          `json:"spec"`
+	Labels     map[string]string        `json:"labels"`
+	Resources  []map[string]interface{} `json:"resources,omitempty"`
+}
+
+// ObjectRef identifies an object created next to a Job.
+type ObjectRef struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Namespace  string `json:"namespace"`
+	Name       string `json:"name"`
+	UID        string `json:"uid"`
 }
@@ -0,0 +1,296 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/kubefill/kubefill/pkg/db"
+	log "github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const (
+	WORKLOAD_LABEL            = "kubefill-workload"
+	STATUS_MAPPING_ANNOTATION = "kubefill.io/status-mapping"
+	PHASE_PENDING             = "Pending"
+	PHASE_RUNNING             = "Running"
+	PHASE_SUCCEEDED           = "Succeeded"
+	PHASE_FAILED              = "Failed"
+)
+
+// defaultStatusMappings holds the status mapping of the workload kinds that
+// need no configuration.
+var defaultStatusMappings = map[string]db.StatusMapping{
+	"v1/Pod": {
+		Running:   &db.StatusMatch{Field: "status.phase", Values: []string{PHASE_RUNNING}},
+		Succeeded: &db.StatusMatch{Field: "status.phase", Values: []string{PHASE_SUCCEEDED}},
+		Failed:    &db.StatusMatch{Field: "status.phase", Values: []string{PHASE_FAILED}},
+	},
+	"batch/v1/CronJob": {
+		Running: &db.StatusMatch{Field: "status.active"},
+	},
+}
+
+// defaultPodTemplatePaths holds where the pod template lives for the kinds
+// that do not use spec.template.
+var defaultPodTemplatePaths = map[string]string{
+	"v1/Pod":           "",
+	"batch/v1/CronJob": "spec.jobTemplate.spec.template",
+}
+
+// ResolveWorkload fills in the defaults of a workload and checks that its
+// phase can be told from its status.
+func ResolveWorkload(workload *db.Workload) (*db.Workload, error) {
+	if workload == nil || (workload.APIVersion == "batch/v1" && workload.Kind == "Job") {
+		return nil, nil
+	}
+
+	if workload.APIVersion == "" || workload.Kind == "" {
+		return nil, fmt.Errorf("workload apiVersion and kind are required")
+	}
+
+	resolved := *workload
+	ref := workload.APIVersion + "/" + workload.Kind
+	mapping := resolved.Status
+
+	if mapping.Running == nil && mapping.Succeeded == nil && mapping.Failed == nil {
+		defaults, ok := defaultStatusMappings[ref]
+
+		if !ok {
+			return nil, fmt.Errorf("workload %s needs a status mapping", ref)
+		}
+
+		resolved.Status = defaults
+	}
+
+	for _, match := range []*db.StatusMatch{resolved.Status.Running, resolved.Status.Succeeded, resolved.Status.Failed} {
+		if match != nil && match.Field == "" {
+			return nil, fmt.Errorf("workload %s status mapping has an empty field", ref)
+		}
+	}
+
+	return &resolved, nil
+}
+
+// RunWorkload creates an object of the application's workload kind after its
+// supporting objects, labelled so the informers can follow it. If anything
+// can not be created, whatever was created is deleted again.
+func (c *Clientset) RunWorkload(name string, labels map[string]string, workload *db.Workload, object map[string]interface{}, objects []map[string]interface{}) (*unstructured.Unstructured, []ObjectRef, error) {
+	ctx := context.TODO()
+	u := &unstructured.Unstructured{Object: object}
+	u.SetAPIVersion(workload.APIVersion)
+	u.SetKind(workload.Kind)
+	u.SetName(name)
+
+	if u.GetNamespace() == "" {
+		u.SetNamespace("default")
+	}
+
+	gvk := u.GroupVersionKind()
+	mapping, err := c.mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	workloadLabels := map[string]string{WORKLOAD_LABEL: workload.Kind}
+
+	for k, v := range labels {
+		workloadLabels[k] = v
+	}
+
+	setLabels(u.Object, workloadLabels)
+
+	if err := setPodTemplateLabels(u.Object, podTemplatePath(workload), workloadLabels); err != nil {
+		return nil, nil, err
+	}
+
+	statusMapping, err := json.Marshal(workload.Status)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	annotations := u.GetAnnotations()
+
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+
+	annotations[STATUS_MAPPING_ANNOTATION] = string(statusMapping)
+	u.SetAnnotations(annotations)
+
+	resources, err := c.prepareResources(u.GetNamespace(), objects)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// The supporting objects, the Secret of the run among them, exist
+	// before the workload so its pods never start without them. Only then
+	// can they be owned by it.
+	owner := metav1.OwnerReference{
+		APIVersion: u.GetAPIVersion(),
+		Kind:       u.GetKind(),
+		Name:       u.GetName(),
+	}
+	created, err := c.createResources(ctx, owner, u.GetNamespace(), labels["job_id"], resources)
+
+	if err != nil {
+		c.deleteResources(ctx, u.GetNamespace(), resources, created)
+		return nil, nil, err
+	}
+
+	client := c.dynamic.Resource(mapping.Resource).Namespace(u.GetNamespace())
+	resp, err := client.Create(ctx, u, metav1.CreateOptions{})
+
+	if err != nil {
+		c.deleteResources(ctx, u.GetNamespace(), resources, created)
+		return nil, nil, err
+	}
+
+	owner.APIVersion = resp.GetAPIVersion()
+	owner.Kind = resp.GetKind()
+	owner.UID = resp.GetUID()
+	err = c.adoptResources(ctx, owner, resp.GetNamespace(), resources, created)
+
+	if err != nil {
+		propagation := metav1.DeletePropagationBackground
+		deleteErr := client.Delete(ctx, resp.GetName(), metav1.DeleteOptions{PropagationPolicy: &propagation})
+
+		if deleteErr != nil {
+			log.Errorf("failed to delete %s %s: %v", resp.GetKind(), resp.GetName(), deleteErr)
+		}
+
+		c.deleteResources(ctx, resp.GetNamespace(), resources, created)
+		return nil, nil, err
+	}
+
+	return resp, created, nil
+}
+
+// WorkloadResource resolves the API resource of a workload kind.
+func (c *Clientset) WorkloadResource(apiVersion string, kind string) (schema.GroupVersionResource, error) {
+	gv, err := schema.ParseGroupVersion(apiVersion)
+
+	if err != nil {
+		return schema.GroupVersionResource{}, err
+	}
+
+	mapping, err := c.mapper.RESTMapping(gv.WithKind(kind).GroupKind(), gv.Version)
+
+	if err != nil {
+		return schema.GroupVersionResource{}, err
+	}
+
+	return mapping.Resource, nil
+}
+
+func podTemplatePath(workload *db.Workload) string {
+	if workload.PodTemplatePath != "" {
+		return workload.PodTemplatePath
+	}
+
+	if path, ok := defaultPodTemplatePaths[workload.APIVersion+"/"+workload.Kind]; ok {
+		return path
+	}
+
+	return "spec.template"
+}
+
+// setPodTemplateLabels labels the pods of the workload so their logs are
+// collected. Workloads without a pod template at path are left alone.
+func setPodTemplateLabels(object map[string]interface{}, path string, labels map[string]string) error {
+	if path == "" {
+		return nil
+	}
+
+	fields := strings.Split(path, ".")
+	template, found, err := unstructured.NestedMap(object, fields...)
+
+	if err != nil || !found {
+		return err
+	}
+
+	setLabels(template, labels)
+	return unstructured.SetNestedMap(object, template, fields...)
+}
+
+func setLabels(object map[string]interface{}, labels map[string]string) {
+	u := unstructured.Unstructured{Object: object}
+	existing := u.GetLabels()
+
+	if existing == nil {
+		existing = make(map[string]string)
+	}
+
+	for k, v := range labels {
+		existing[k] = v
+	}
+
+	u.SetLabels(existing)
+}
+
+// WorkloadPhase tells the phase of a workload from the status mapping it was
+// created with.
+func WorkloadPhase(u *unstructured.Unstructured) string {
+	var mapping db.StatusMapping
+	err := json.Unmarshal([]byte(u.GetAnnotations()[STATUS_MAPPING_ANNOTATION]), &mapping)
+
+	if err != nil {
+		return PHASE_PENDING
+	}
+
+	if statusMatches(u.Object, mapping.Failed) {
+		return PHASE_FAILED
+	}
+
+	if statusMatches(u.Object, mapping.Succeeded) {
+		return PHASE_SUCCEEDED
+	}
+
+	if statusMatches(u.Object, mapping.Running) {
+		return PHASE_RUNNING
+	}
+
+	return PHASE_PENDING
+}
+
+func statusMatches(object map[string]interface{}, match *db.StatusMatch) bool {
+	if match == nil {
+		return false
+	}
+
+	value, found, err := unstructured.NestedFieldNoCopy(object, strings.Split(match.Field, ".")...)
+
+	if err != nil || !found || value == nil {
+		return false
+	}
+
+	if len(match.Values) == 0 {
+		switch v := value.(type) {
+		case string:
+			return v != ""
+		case []interface{}:
+			return len(v) > 0
+		case map[string]interface{}:
+			return len(v) > 0
+		case bool:
+			return v
+		}
+
+		return true
+	}
+
+	for _, expected := range match.Values {
+		if fmt.Sprint(value) == expected {
+			return true
+		}
+	}
+
+	return false
+}
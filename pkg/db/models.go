@@ -9,12 +9,38 @@ import (
 type Application struct {
 	ID           uint `gorm:"primary_key" json:"id"`
 	gorm.Model   `json:"model"`
-	Name         string `json:"name"`
-	RepoID       uint   `json:"repo_id"`
-	ManifestPath string `json:"manifest_path"`
-	Status       int    `json:"status"`
-	Jobs         []Job
-	Secrets      []Secret
+	Name         string    `json:"name"`
+	RepoID       uint      `json:"repo_id"`
+	ManifestPath string    `json:"manifest_path"`
+	ManifestType string    `json:"manifest_type"`
+	ChartPath    string    `json:"chart_path"`
+	Workload     *Workload `gorm:"serializer:json" json:"workload"`
+	Status       int       `json:"status"`
+	Discovered   bool      `json:"discovered"`
+	// GitOwned applications are declared in the .kubefill.yaml of their repo
+	// and only change through it.
+	GitOwned    bool `json:"git_owned"`
+	AppSettings `gorm:"embedded"`
+	Jobs        []Job
+	Secrets     []Secret
+}
+
+// AppSettings controls how the jobs of an application run. Concurrency 0
+// runs any number of jobs at once.
+type AppSettings struct {
+	Concurrency     int                  `json:"concurrency"`
+	Schedules       []string             `gorm:"serializer:json" json:"schedules"`
+	RequiredSecrets []string             `gorm:"serializer:json" json:"required_secrets"`
+	Notifications   []NotificationTarget `gorm:"serializer:json" json:"notifications"`
+}
+
+// NotificationTarget is told about the jobs of an application which reach
+// one of the Phases, or any final phase when Phases is empty. Target is a URL
+// for webhook and slack targets and an address for email targets.
+type NotificationTarget struct {
+	Type   string   `json:"type"`
+	Target string   `json:"target"`
+	Phases []string `json:"phases,omitempty"`
 }
 
 type Job struct {
@@ -25,15 +51,77 @@ type Job struct {
 	Phase         string         `json:"phase"`
 	Spec          datatypes.JSON `json:"spec"`
 	Meta          datatypes.JSON `json:"meta"`
+	Resources     datatypes.JSON `json:"resources"`
+	Kind          string         `json:"kind"`
+	// Ref is set when the job ran off another ref than the repo branch, and
+	// Commit is the commit it resolved to.
+	Ref    string `json:"ref,omitempty"`
+	Commit string `json:"commit,omitempty"`
+}
+
+// Workload declares the kind of object an application runs when it is not a
+// batch/v1 Job, and which of its fields tell the run phase.
+type Workload struct {
+	APIVersion      string        `json:"apiVersion"`
+	Kind            string        `json:"kind"`
+	Status          StatusMapping `json:"status"`
+	PodTemplatePath string        `json:"podTemplatePath,omitempty"`
+}
+
+type StatusMapping struct {
+	Running   *StatusMatch `json:"running,omitempty"`
+	Succeeded *StatusMatch `json:"succeeded,omitempty"`
+	Failed    *StatusMatch `json:"failed,omitempty"`
+}
+
+// StatusMatch matches when the dot separated Field holds one of Values, or
+// when Values is empty, when the field is set to a non empty value.
+type StatusMatch struct {
+	Field  string   `json:"field"`
+	Values []string `json:"values,omitempty"`
 }
 
 type Repo struct {
-	ID         uint `gorm:"primary_key" json:"id"`
-	gorm.Model `json:"model"`
-	Url        string `json:"url"`
-	Branch     string `json:"branch"`
-	Hash       string `json:"hash"`
-	Commit     string `json:"commit"`
+	ID              uint `gorm:"primary_key" json:"id"`
+	gorm.Model      `json:"model"`
+	Url             string `json:"url"`
+	Branch          string `json:"branch"`
+	Hash            string `json:"hash"`
+	Commit          string `json:"commit"`
+	WebhookSecret   string `json:"-"`
+	PollInterval    int    `json:"poll_interval"`
+	AutoDiscover    bool   `json:"auto_discover"`
+	CloneOptions    `gorm:"embedded"`
+	SignaturePolicy `gorm:"embedded"`
+	// PolicyFailure holds why the last synced commit was rejected, empty
+	// once a commit is accepted again.
+	PolicyFailure string `json:"policy_failure"`
+	// DeployKey is the public half of the key generated by the reposerver.
+	// PendingDeployKey replaces it once a rotation is confirmed.
+	DeployKey        string `json:"deploy_key"`
+	PendingDeployKey string `json:"pending_deploy_key"`
+	// ConfigErrors lists why the .kubefill.yaml of the synced commit was not
+	// applied, empty once it applies again.
+	ConfigErrors []string `gorm:"serializer:json" json:"config_errors"`
+	// DiscoveryErrors lists the manifest directories of auto discovery which
+	// do not parse, their applications are kept until they parse again.
+	DiscoveryErrors []string `gorm:"serializer:json" json:"discovery_errors"`
+}
+
+// SignaturePolicy requires synced commits to be signed by one of the trusted
+// keys, armored GPG public keys or SSH keys in authorized_keys format.
+type SignaturePolicy struct {
+	VerifySignatures bool     `json:"verify_signatures"`
+	TrustedKeys      []string `gorm:"serializer:json" json:"trusted_keys"`
+}
+
+// CloneOptions controls how the reposerver clones a repo. Depth 0 clones the
+// full history, Sparse limits the checkout to the applications' paths.
+type CloneOptions struct {
+	Depth      int  `json:"depth"`
+	Submodules bool `json:"submodules"`
+	Sparse     bool `json:"sparse"`
+	Lfs        bool `json:"lfs"`
 }
 
 type Secret struct {
@@ -0,0 +1,338 @@
+package grpcauth
+
+import (
+	"context"
+	"crypto/subtle"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	AUTHORIZATION_HEADER = "authorization"
+	BEARER_PREFIX        = "Bearer "
+)
+
+// ServerOptions returns the options of the reposerver gRPC server: TLS or
+// mutual TLS credentials and the interceptors checking the service token.
+// Certificates are reloaded from their files when they change, so rotated
+// certificates are served without a restart.
+func ServerOptions(config Config) ([]grpc.ServerOption, error) {
+	var opts []grpc.ServerOption
+
+	if config.CertFile != "" || config.KeyFile != "" {
+		cert := &keyPair{certFile: config.CertFile, keyFile: config.KeyFile}
+
+		if _, err := cert.get(); err != nil {
+			return nil, err
+		}
+
+		var clientCAs *certPool
+
+		if config.CAFile != "" {
+			clientCAs = &certPool{caFile: config.CAFile}
+
+			if _, err := clientCAs.get(); err != nil {
+				return nil, err
+			}
+		}
+
+		tlsConfig := &tls.Config{
+			MinVersion: tls.VersionTLS12,
+			GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+				certificate, err := cert.get()
+
+				if err != nil {
+					return nil, err
+				}
+
+				c := &tls.Config{
+					MinVersion:   tls.VersionTLS12,
+					Certificates: []tls.Certificate{*certificate},
+				}
+
+				if clientCAs != nil {
+					pool, err := clientCAs.get()
+
+					if err != nil {
+						return nil, err
+					}
+
+					c.ClientCAs = pool
+					c.ClientAuth = tls.RequireAndVerifyClientCert
+				}
+
+				return c, nil
+			},
+		}
+
+		opts = append(opts, grpc.Creds(credentials.NewTLS(tlsConfig)))
+	} else if config.CAFile != "" {
+		return nil, errors.New("mutual TLS requires a server certificate and key")
+	} else if config.Token != "" {
+		if err := plainTextToken(config); err != nil {
+			return nil, err
+		}
+	}
+
+	if config.Token != "" {
+		opts = append(opts,
+			grpc.UnaryInterceptor(func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+				if err := checkToken(ctx, config.Token); err != nil {
+					return nil, err
+				}
+
+				return handler(ctx, req)
+			}),
+			grpc.StreamInterceptor(func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+				if err := checkToken(ss.Context(), config.Token); err != nil {
+					return err
+				}
+
+				return handler(srv, ss)
+			}),
+		)
+	} else {
+		log.Warnln("No service token is configured, the repo server accepts unauthenticated calls")
+	}
+
+	return opts, nil
+}
+
+func checkToken(ctx context.Context, token string) error {
+	md, ok := metadata.FromIncomingContext(ctx)
+
+	if !ok {
+		return status.Error(codes.Unauthenticated, "missing service token")
+	}
+
+	for _, value := range md.Get(AUTHORIZATION_HEADER) {
+		if !strings.HasPrefix(value, BEARER_PREFIX) {
+			continue
+		}
+
+		if subtle.ConstantTimeCompare([]byte(strings.TrimPrefix(value, BEARER_PREFIX)), []byte(token)) == 1 {
+			return nil
+		}
+	}
+
+	return status.Error(codes.Unauthenticated, "invalid service token")
+}
+
+func NewDialer(config Config) (*Dialer, error) {
+	d := &Dialer{config: config}
+
+	if config.CertFile != "" || config.KeyFile != "" {
+		d.cert = &keyPair{certFile: config.CertFile, keyFile: config.KeyFile}
+
+		if _, err := d.cert.get(); err != nil {
+			return nil, err
+		}
+	}
+
+	if config.CAFile != "" {
+		d.rootCAs = &certPool{caFile: config.CAFile}
+
+		if _, err := d.rootCAs.get(); err != nil {
+			return nil, err
+		}
+	}
+
+	if config.Token != "" && !d.secure() {
+		if err := plainTextToken(config); err != nil {
+			return nil, err
+		}
+	}
+
+	return d, nil
+}
+
+// plainTextToken refuses a service token without TLS when RequireTLS is
+// set, and warns otherwise: anyone able to see the traffic can replay it.
+func plainTextToken(config Config) error {
+	if config.RequireTLS {
+		return errors.New("the service token requires TLS, configure certificates or unset the TLS requirement")
+	}
+
+	log.Warnln("WARNING: TLS is not configured, the repo server token is sent in clear text and can be replayed by anyone seeing the traffic")
+	return nil
+}
+
+func (d *Dialer) secure() bool {
+	return d.config.TLS || d.cert != nil || d.rootCAs != nil
+}
+
+func (d *Dialer) Dial(address string) (*grpc.ClientConn, error) {
+	opts, err := d.dialOptions()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return grpc.Dial(address, opts...)
+}
+
+func (d *Dialer) dialOptions() ([]grpc.DialOption, error) {
+	var opts []grpc.DialOption
+
+	if d.secure() {
+		tlsConfig := &tls.Config{
+			MinVersion: tls.VersionTLS12,
+			ServerName: d.config.ServerName,
+		}
+
+		if d.rootCAs != nil {
+			pool, err := d.rootCAs.get()
+
+			if err != nil {
+				return nil, err
+			}
+
+			tlsConfig.RootCAs = pool
+		}
+
+		if d.cert != nil {
+			tlsConfig.GetClientCertificate = func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+				return d.cert.get()
+			}
+		}
+
+		opts = append(opts, grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig)))
+	} else {
+		opts = append(opts, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	}
+
+	if d.config.Token != "" {
+		opts = append(opts, grpc.WithPerRPCCredentials(tokenCredentials{token: d.config.Token, secure: d.secure()}))
+	}
+
+	return opts, nil
+}
+
+func (t tokenCredentials) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	return map[string]string{AUTHORIZATION_HEADER: BEARER_PREFIX + t.token}, nil
+}
+
+func (t tokenCredentials) RequireTransportSecurity() bool {
+	return t.secure
+}
+
+// get returns the key pair, reading it again when one of its files was
+// modified since the last read. A broken rotation keeps the previous pair.
+func (k *keyPair) get() (*tls.Certificate, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	modTime, err := latestModTime(k.certFile, k.keyFile)
+
+	if err != nil {
+		if k.cert != nil {
+			log.Errorln(err)
+			return k.cert, nil
+		}
+
+		return nil, err
+	}
+
+	if k.cert != nil && modTime.Equal(k.modTime) {
+		return k.cert, nil
+	}
+
+	cert, err := tls.LoadX509KeyPair(k.certFile, k.keyFile)
+
+	if err != nil {
+		if k.cert != nil {
+			log.Errorf("Failed to reload %s: %v", k.certFile, err)
+			return k.cert, nil
+		}
+
+		return nil, err
+	}
+
+	if k.cert != nil {
+		log.Infof("Reloaded certificate %s", k.certFile)
+	}
+
+	k.cert = &cert
+	k.modTime = modTime
+	return k.cert, nil
+}
+
+// get returns the CA pool, reading it again when its file was modified since
+// the last read. A broken rotation keeps the previous pool.
+func (c *certPool) get() (*x509.CertPool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	modTime, err := latestModTime(c.caFile)
+
+	if err != nil {
+		if c.pool != nil {
+			log.Errorln(err)
+			return c.pool, nil
+		}
+
+		return nil, err
+	}
+
+	if c.pool != nil && modTime.Equal(c.modTime) {
+		return c.pool, nil
+	}
+
+	contents, err := os.ReadFile(c.caFile)
+
+	if err == nil {
+		pool := x509.NewCertPool()
+
+		if pool.AppendCertsFromPEM(contents) {
+			if c.pool != nil {
+				log.Infof("Reloaded CA %s", c.caFile)
+			}
+
+			c.pool = pool
+			c.modTime = modTime
+			return c.pool, nil
+		}
+
+		err = fmt.Errorf("no certificates found in %s", c.caFile)
+	}
+
+	if c.pool != nil {
+		log.Errorf("Failed to reload %s: %v", c.caFile, err)
+		return c.pool, nil
+	}
+
+	return nil, err
+}
+
+// latestModTime follows symlinks, so the atomic swap of a mounted Kubernetes
+// secret is seen as a modification.
+func latestModTime(files ...string) (time.Time, error) {
+	var latest time.Time
+
+	for _, file := range files {
+		info, err := os.Stat(file)
+
+		if err != nil {
+			return latest, err
+		}
+
+		if info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+	}
+
+	return latest, nil
+}
@@ -0,0 +1,59 @@
+package grpcauth
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"sync"
+	"time"
+)
+
+// Config secures the channel between the API server and the reposerver.
+//
+// On the reposerver TLS is enabled by CertFile and KeyFile, and CAFile makes
+// it require client certificates signed by that CA. On the API server TLS is
+// enabled by TLS, CAFile or CertFile: CAFile verifies the reposerver instead
+// of the system roots and CertFile and KeyFile are presented as the client
+// certificate. Token is the shared service token sent with every call, and
+// RequireTLS refuses to send or accept it over a plain text channel.
+type Config struct {
+	TLS        bool
+	CertFile   string
+	KeyFile    string
+	CAFile     string
+	ServerName string
+	Token      string
+	RequireTLS bool
+}
+
+// Dialer opens connections to the reposerver with the credentials of a
+// Config. It keeps the certificate files loaded between dials and reloads
+// them when they change.
+type Dialer struct {
+	config  Config
+	cert    *keyPair
+	rootCAs *certPool
+}
+
+// keyPair is a certificate and key read from files, reloaded when either
+// file changes.
+type keyPair struct {
+	certFile string
+	keyFile  string
+	mu       sync.Mutex
+	cert     *tls.Certificate
+	modTime  time.Time
+}
+
+// certPool is a CA bundle read from a file, reloaded when it changes.
+type certPool struct {
+	caFile  string
+	mu      sync.Mutex
+	pool    *x509.CertPool
+	modTime time.Time
+}
+
+// tokenCredentials sends the service token with every call.
+type tokenCredentials struct {
+	token  string
+	secure bool
+}
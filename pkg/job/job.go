@@ -1,9 +1,22 @@
 package job
 
 import (
+	"errors"
+	"fmt"
+
 	"github.com/kubefill/kubefill/pkg/db"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// PHASE_PENDING is the phase of a job created but not seen running yet, the
+// same as the pod phase.
+const PHASE_PENDING = "Pending"
+
+// ACTIVE_PHASES are the phases of jobs counted against the concurrency of
+// their application.
+var ACTIVE_PHASES = []string{PHASE_PENDING, "Running"}
+
 func NewService(db *db.Connection) *JobService {
 	return &JobService{
 		db: db,
@@ -17,11 +30,92 @@ func (s *JobService) GetAllByAppId(appId uint) ([]db.Job, error) {
 }
 
 func (s *JobService) Create(data Job) db.Job {
-	job := db.Job{Name: data.Name, ApplicationID: data.ApplicationID}
+	job := db.Job{Name: data.Name, ApplicationID: data.ApplicationID, Phase: PHASE_PENDING, Ref: data.Ref, Commit: data.Commit}
 	s.db.Create(&job)
 	return job
 }
 
+// CreateWithin creates a Pending job unless concurrency jobs of the
+// application are active already, 0 for no limit. The application row is
+// locked from the count to the insert, so concurrent runs, of any server,
+// can not all pass the check.
+func (s *JobService) CreateWithin(data Job, concurrency int) (db.Job, error) {
+	job := db.Job{Name: data.Name, ApplicationID: data.ApplicationID, Phase: PHASE_PENDING, Ref: data.Ref, Commit: data.Commit}
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if concurrency > 0 {
+			err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&db.Application{}, data.ApplicationID).Error
+
+			if err != nil {
+				return err
+			}
+
+			var active int64
+			err = tx.Model(&db.Job{}).Where("application_id = ? AND phase IN ?", data.ApplicationID, ACTIVE_PHASES).Count(&active).Error
+
+			if err != nil {
+				return err
+			}
+
+			if active >= int64(concurrency) {
+				return &ConcurrencyError{Active: active, Concurrency: concurrency}
+			}
+		}
+
+		return tx.Create(&job).Error
+	})
+
+	return job, err
+}
+
+// SetPhase records the phase of a job and tells the phase hook when it
+// changed. A job deleted meanwhile is ignored.
+func (s *JobService) SetPhase(id uint, phase string) error {
+	job, err := s.Get(id)
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if job.Phase == phase {
+		return nil
+	}
+
+	previous := job.Phase
+	err = s.db.Model(&job).Update("phase", phase).Error
+
+	if err != nil {
+		return err
+	}
+
+	job.Phase = phase
+
+	if s.onPhase != nil {
+		s.onPhase(job, previous)
+	}
+
+	return nil
+}
+
+// OnPhase registers the hook told about every phase change of a job, with
+// the job holding its new phase.
+func (s *JobService) OnPhase(hook func(job db.Job, previous string)) {
+	s.onPhase = hook
+}
+
+// UpdateRun saves what starting a run sets on its job, leaving the phase,
+// which the informer may have changed meanwhile, as it is.
+func (s *JobService) UpdateRun(job db.Job) error {
+	return s.db.Model(&job).Select("spec", "meta", "kind", "resources").Updates(&job).Error
+}
+
+func (e *ConcurrencyError) Error() string {
+	return fmt.Sprintf("%d jobs of the application are running, its concurrency is %d", e.Active, e.Concurrency)
+}
+
 func (s *JobService) Get(id uint) (db.Job, error) {
 	job := db.Job{}
 	err := s.db.First(&job, id).Error
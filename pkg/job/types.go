@@ -10,11 +10,21 @@ type Job struct {
 	Name          string `json:"name"`
 	Phase         string `json:"phase"`
 	Spec          string `json:"spec"`
+	Ref           string `json:"ref,omitempty"`
+	Commit        string `json:"commit,omitempty"`
 	Created_At    string `json:"created_at"`
 	Updated_At    string `json:"updated_at"`
 	Deleted_At    string `json:"deleted_at"`
 }
 
 type JobService struct {
-	db *db.Connection
+	db      *db.Connection
+	onPhase func(job db.Job, previous string)
+}
+
+// ConcurrencyError rejects a run while the application has Concurrency
+// active jobs.
+type ConcurrencyError struct {
+	Active      int64
+	Concurrency int
 }
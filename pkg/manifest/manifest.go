@@ -0,0 +1,140 @@
+package manifest
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+)
+
+// Parse splits a YAML or JSON stream whose workload is a batch/v1 Job.
+func Parse(data []byte) (*Manifest, error) {
+	return ParseWorkload(data, "")
+}
+
+// ParseWorkload splits a YAML or JSON stream into its documents. The document
+// of the workload kind, or a document without a kind as used by plain data
+// files, becomes the Job. Every other document must be a complete Kubernetes
+// object. An empty workload means a batch/v1 Job.
+func ParseWorkload(data []byte, workload string) (*Manifest, error) {
+	workloadAPIVersion, workloadKind := SplitWorkload(workload)
+	decoder := utilyaml.NewYAMLOrJSONDecoder(bytes.NewReader(data), 4096)
+	manifest := Manifest{}
+
+	for index := 0; ; index++ {
+		var document map[string]interface{}
+		err := decoder.Decode(&document)
+
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		if len(document) == 0 {
+			continue
+		}
+
+		apiVersion, _ := document["apiVersion"].(string)
+		kind, _ := document["kind"].(string)
+
+		if kind == "" || (apiVersion == workloadAPIVersion && kind == workloadKind) {
+			if manifest.Job != nil {
+				return nil, fmt.Errorf("manifest contains more than one %s", workloadKind)
+			}
+
+			manifest.Job = workloadDocument(document, workloadAPIVersion, workloadKind)
+			continue
+		}
+
+		if apiVersion == "" {
+			return nil, fmt.Errorf("document %d: %s has no apiVersion", index+1, kind)
+		}
+
+		metadata, _ := document["metadata"].(map[string]interface{})
+
+		if name, _ := metadata["name"].(string); name == "" {
+			return nil, fmt.Errorf("document %d: %s %s has no metadata.name", index+1, apiVersion, kind)
+		}
+
+		manifest.Resources = append(manifest.Resources, document)
+	}
+
+	if manifest.Job == nil {
+		return nil, fmt.Errorf("manifest does not contain a %s/%s", workloadAPIVersion, workloadKind)
+	}
+
+	return &manifest, nil
+}
+
+// workloadDocument shapes a batch Job like client.JobConfig. Other workloads
+// keep the whole object, with apiVersion and kind filled in when missing.
+func workloadDocument(document map[string]interface{}, apiVersion string, kind string) map[string]interface{} {
+	if IsJob(Workload(apiVersion, kind)) {
+		if _, ok := document["kind"]; !ok {
+			return document
+		}
+
+		return map[string]interface{}{"metadata": document["metadata"], "spec": document["spec"]}
+	}
+
+	document["apiVersion"] = apiVersion
+	document["kind"] = kind
+	return document
+}
+
+// Workload formats a workload reference, e.g. argoproj.io/v1alpha1/Workflow.
+func Workload(apiVersion string, kind string) string {
+	if apiVersion == "" && kind == "" {
+		return ""
+	}
+
+	return apiVersion + "/" + kind
+}
+
+// SplitWorkload splits a workload reference into its apiVersion and kind.
+// An empty reference is a batch/v1 Job.
+func SplitWorkload(workload string) (string, string) {
+	index := strings.LastIndex(workload, "/")
+
+	if index < 0 {
+		return JOB_API_VERSION, JOB_KIND
+	}
+
+	return workload[:index], workload[index+1:]
+}
+
+func IsJob(workload string) bool {
+	apiVersion, kind := SplitWorkload(workload)
+	return apiVersion == JOB_API_VERSION && kind == JOB_KIND
+}
+
+// MergePatch applies patch onto target as a JSON merge patch (RFC 7386).
+func MergePatch(target map[string]interface{}, patch map[string]interface{}) map[string]interface{} {
+	if target == nil {
+		target = make(map[string]interface{})
+	}
+
+	for key, value := range patch {
+		if value == nil {
+			delete(target, key)
+			continue
+		}
+
+		patchMap, ok := value.(map[string]interface{})
+
+		if !ok {
+			target[key] = value
+			continue
+		}
+
+		targetMap, _ := target[key].(map[string]interface{})
+		target[key] = MergePatch(targetMap, patchMap)
+	}
+
+	return target
+}
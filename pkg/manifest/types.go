@@ -0,0 +1,14 @@
+package manifest
+
+const (
+	JOB_API_VERSION = "batch/v1"
+	JOB_KIND        = "Job"
+)
+
+// Manifest is an application manifest split into its workload and the
+// supporting objects created next to it. A batch Job workload is shaped like
+// client.JobConfig, any other workload is the complete object.
+type Manifest struct {
+	Job       map[string]interface{}
+	Resources []map[string]interface{}
+}
@@ -1,7 +1,11 @@
 package repo
 
 import (
+	"errors"
+
 	"github.com/kubefill/kubefill/pkg/db"
+	"github.com/kubefill/kubefill/pkg/signature"
+	"gorm.io/gorm"
 )
 
 func NewService(db *db.Connection) *Service {
@@ -16,8 +20,23 @@ func (s *Service) List() []Repo {
 	return repos
 }
 
+// Find lists the repos like List, failing instead of returning none when
+// the query fails.
+func (s *Service) Find() ([]Repo, error) {
+	var repos []Repo
+	err := s.db.Find(&repos).Error
+	return repos, err
+}
+
 func (s *Service) Create(payload Repo) db.Repo {
-	repo := db.Repo{Url: payload.Url, Branch: payload.Branch}
+	repo := db.Repo{
+		Url:             payload.Url,
+		Branch:          payload.Branch,
+		WebhookSecret:   payload.WebhookSecret,
+		PollInterval:    payload.PollInterval,
+		CloneOptions:    payload.CloneOptions,
+		SignaturePolicy: payload.SignaturePolicy,
+	}
 	s.db.Create(&repo)
 	return repo
 }
@@ -52,3 +71,39 @@ func (s *Service) Delete(repo db.Repo) error {
 
 	return nil
 }
+
+// DeleteCascade deletes a repo together with its applications and their
+// secrets. Jobs are kept, like when an application is deleted.
+func (s *Service) DeleteCascade(repo db.Repo) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		appIds := tx.Model(&db.Application{}).Select("id").Where("repo_id = ?", repo.ID)
+		err := tx.Unscoped().Where("application_id IN (?)", appIds).Delete(&db.Secret{}).Error
+
+		if err != nil {
+			return err
+		}
+
+		err = tx.Unscoped().Where("repo_id = ?", repo.ID).Delete(&db.Application{}).Error
+
+		if err != nil {
+			return err
+		}
+
+		return tx.Unscoped().Delete(&repo).Error
+	})
+}
+
+// ValidateSignaturePolicy checks that a policy verifying signatures has
+// trusted keys, each an armored GPG public key or SSH authorized keys.
+func ValidateSignaturePolicy(policy db.SignaturePolicy) error {
+	if !policy.VerifySignatures {
+		return nil
+	}
+
+	if len(policy.TrustedKeys) == 0 {
+		return errors.New("verify_signatures needs at least one trusted key")
+	}
+
+	_, _, err := signature.TrustedKeys(policy.TrustedKeys)
+	return err
+}
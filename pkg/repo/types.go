@@ -2,27 +2,52 @@ package repo
 
 import "github.com/kubefill/kubefill/pkg/db"
 
+const (
+	// POLL_DEFAULT polls the repo at the server wide refresh interval.
+	POLL_DEFAULT = 0
+	// POLL_DISABLED only syncs the repo manually or from webhooks.
+	POLL_DISABLED = -1
+)
+
 type Repo struct {
-	Id         int    `json:"id"`
-	Url        string `json:"url"`
-	Commit     string `json:"commit"`
-	Hash       string `json:"hash"`
-	Branch     string `json:"branch"`
-	Created_At string `json:"created_at"`
-	Updated_At string `json:"updated_at"`
-	Deleted_At string `json:"deleted_at"`
+	Id            int    `json:"id"`
+	Url           string `json:"url"`
+	Commit        string `json:"commit"`
+	Hash          string `json:"hash"`
+	Branch        string `json:"branch"`
+	WebhookSecret string `json:"-"`
+	PollInterval  int    `json:"poll_interval"`
+	AutoDiscover  bool   `json:"auto_discover"`
+	db.CloneOptions
+	db.SignaturePolicy
+	PolicyFailure    string   `json:"policy_failure"`
+	DeployKey        string   `json:"deploy_key"`
+	PendingDeployKey string   `json:"pending_deploy_key"`
+	ConfigErrors     []string `gorm:"serializer:json" json:"config_errors"`
+	DiscoveryErrors  []string `gorm:"serializer:json" json:"discovery_errors"`
+	Created_At       string   `json:"created_at"`
+	Updated_At       string   `json:"updated_at"`
+	Deleted_At       string   `json:"deleted_at"`
 }
 
 type RepoCreate struct {
 	Url             string `json:"url"`
 	Branch          string `json:"branch"`
 	Ssh_Private_Key string `json:"ssh_private_key"`
+	Webhook_Secret  string `json:"webhook_secret"`
+	Poll_Interval   int    `json:"poll_interval"`
+	db.CloneOptions
+	db.SignaturePolicy
 }
 
 type RepoUpdate struct {
 	Url             string `json:"url"`
 	Branch          string `json:"branch"`
 	Ssh_Private_Key string `json:"ssh_private_key"`
+	Webhook_Secret  string `json:"webhook_secret"`
+	Poll_Interval   int    `json:"poll_interval"`
+	db.CloneOptions
+	db.SignaturePolicy
 }
 
 type Service struct {
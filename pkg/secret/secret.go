@@ -1,7 +1,11 @@
 package secret
 
 import (
+	"fmt"
+
 	"github.com/kubefill/kubefill/pkg/db"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 func NewService(db *db.Connection) *SecretService {
@@ -63,3 +67,74 @@ func (s *SecretService) Delete(secret db.Secret) error {
 
 	return nil
 }
+
+// Rewrite replaces the value of every secret and of every repo webhook
+// secret with the one rewrite returns, in a single transaction, and returns
+// how many of each changed. Values rewrite returns false for are left as they
+// are, an error rolls all of them back, so keys are never half rotated.
+func (s *SecretService) Rewrite(rewrite func(value string) (string, bool, error)) (int, int, error) {
+	secretsRewritten, webhookSecretsRewritten := 0, 0
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var secrets []db.Secret
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Find(&secrets).Error
+
+		if err != nil {
+			return err
+		}
+
+		for _, secret := range secrets {
+			value, changed, err := rewrite(secret.Value)
+
+			if err != nil {
+				return fmt.Errorf("secret %d (%s): %w", secret.ID, secret.Name, err)
+			}
+
+			if !changed {
+				continue
+			}
+
+			err = tx.Model(&secret).Update("value", value).Error
+
+			if err != nil {
+				return err
+			}
+
+			secretsRewritten++
+		}
+
+		var repos []db.Repo
+		err = tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("webhook_secret <> ''").Find(&repos).Error
+
+		if err != nil {
+			return err
+		}
+
+		for _, repo := range repos {
+			value, changed, err := rewrite(repo.WebhookSecret)
+
+			if err != nil {
+				return fmt.Errorf("webhook secret of repo %d: %w", repo.ID, err)
+			}
+
+			if !changed {
+				continue
+			}
+
+			err = tx.Model(&repo).Update("webhook_secret", value).Error
+
+			if err != nil {
+				return err
+			}
+
+			webhookSecretsRewritten++
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return secretsRewritten, webhookSecretsRewritten, nil
+}
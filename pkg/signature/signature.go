@@ -0,0 +1,43 @@
+package signature
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/ssh"
+)
+
+const PGP_KEY_HEADER = "-----BEGIN PGP PUBLIC KEY BLOCK-----"
+
+// TrustedKeys splits the trusted keys of a signature policy into armored PGP
+// key rings and SSH public keys in authorized_keys format. A key which is
+// neither is an error.
+func TrustedKeys(keys []string) ([]string, []ssh.PublicKey, error) {
+	var pgpKeys []string
+	var sshKeys []ssh.PublicKey
+
+	for _, key := range keys {
+		key = strings.TrimSpace(key)
+
+		if strings.Contains(key, PGP_KEY_HEADER) {
+			pgpKeys = append(pgpKeys, key)
+			continue
+		}
+
+		rest := []byte(key)
+
+		for len(bytes.TrimSpace(rest)) > 0 {
+			publicKey, _, _, next, err := ssh.ParseAuthorizedKey(rest)
+
+			if err != nil {
+				return nil, nil, fmt.Errorf("trusted key is neither a GPG public key nor an SSH public key: %w", err)
+			}
+
+			sshKeys = append(sshKeys, publicKey)
+			rest = next
+		}
+	}
+
+	return pgpKeys, sshKeys, nil
+}
@@ -0,0 +1,189 @@
+package tmpl
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"text/template"
+
+	"github.com/kubefill/kubefill/pkg/manifest"
+)
+
+var (
+	templateErrorLine = regexp.MustCompile(`^template: [^:]*:(\d+)(?::\d+)?: (?:executing "[^"]*" at <[^>]*>: )?(.*)$`)
+	yamlErrorLine     = regexp.MustCompile(`line (\d+): (.*)$`)
+	secretField       = regexp.MustCompile(`\.` + SECRETS_KEY + `\.([A-Za-z_][A-Za-z0-9_]*)`)
+	missingKey        = regexp.MustCompile(`^map has no entry for key "([^"]*)"$`)
+	secretIndex       = regexp.MustCompile(`index\s+\$?\.` + SECRETS_KEY + `\s+"([^"]+)"`)
+)
+
+// funcs is the function library available to templates. It deliberately
+// leaves out anything that touches the environment, files or network.
+var funcs = template.FuncMap{
+	"default":  defaultValue,
+	"quote":    quote,
+	"toJson":   toJson,
+	"b64enc":   b64enc,
+	"required": required,
+}
+
+// Parse checks the template syntax.
+func Parse(contents string) error {
+	_, err := parse(contents)
+	return err
+}
+
+// SecretRefs returns the names of the secrets a template reads from
+// SECRETS_KEY, as a field or with index and a literal name, sorted and
+// without duplicates.
+func SecretRefs(contents string) []string {
+	seen := make(map[string]bool)
+
+	for _, pattern := range []*regexp.Regexp{secretField, secretIndex} {
+		for _, match := range pattern.FindAllStringSubmatch(contents, -1) {
+			seen[match[1]] = true
+		}
+	}
+
+	names := make([]string, 0, len(seen))
+
+	for name := range seen {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+	return names
+}
+
+// Render executes the template with the form params and secrets and splits
+// the result into the workload and its supporting objects.
+func Render(contents string, params map[string]interface{}, secrets map[string]string, workload string) (*manifest.Manifest, error) {
+	t, err := parse(contents)
+
+	if err != nil {
+		return nil, err
+	}
+
+	inputs := make(map[string]interface{}, len(params)+1)
+
+	for key, value := range params {
+		inputs[key] = value
+	}
+
+	inputs[SECRETS_KEY] = secrets
+
+	var rendered bytes.Buffer
+	err = t.Execute(&rendered, inputs)
+
+	if err != nil {
+		return nil, templateError(err)
+	}
+
+	parsed, err := manifest.ParseWorkload(rendered.Bytes(), workload)
+
+	if err != nil {
+		renderError := &RenderError{Rendered: true, Message: err.Error()}
+
+		if match := yamlErrorLine.FindStringSubmatch(err.Error()); match != nil {
+			renderError.Line, _ = strconv.Atoi(match[1])
+			renderError.Message = match[2]
+		}
+
+		return nil, renderError
+	}
+
+	return parsed, nil
+}
+
+func parse(contents string) (*template.Template, error) {
+	// A param left out of the form fails rather than rendering "<no value>",
+	// optional params are read with index, which yields nil.
+	t, err := template.New(TEMPLATE_FILE).Option("missingkey=error").Funcs(funcs).Parse(contents)
+
+	if err != nil {
+		return nil, templateError(err)
+	}
+
+	return t, nil
+}
+
+func templateError(err error) error {
+	var execError template.ExecError
+
+	if errors.As(err, &execError) {
+		err = execError.Err
+	}
+
+	match := templateErrorLine.FindStringSubmatch(err.Error())
+
+	if match == nil {
+		return &RenderError{Message: err.Error()}
+	}
+
+	line, _ := strconv.Atoi(match[1])
+	message := match[2]
+
+	if key := missingKey.FindStringSubmatch(message); key != nil {
+		message = fmt.Sprintf("%s is not set, read optional values with (index . %q)", key[1], key[1])
+	}
+
+	return &RenderError{Line: line, Message: message}
+}
+
+func empty(value interface{}) bool {
+	if value == nil {
+		return true
+	}
+
+	v := reflect.ValueOf(value)
+
+	switch v.Kind() {
+	case reflect.String, reflect.Slice, reflect.Map, reflect.Array:
+		return v.Len() == 0
+	case reflect.Ptr, reflect.Interface:
+		return v.IsNil()
+	}
+
+	return v.IsZero()
+}
+
+// defaultValue returns def when value is empty: {{ .retries | default 3 }}.
+func defaultValue(def interface{}, value ...interface{}) interface{} {
+	if len(value) == 0 || empty(value[0]) {
+		return def
+	}
+
+	return value[0]
+}
+
+func quote(value interface{}) string {
+	if value == nil {
+		return `""`
+	}
+
+	return strconv.Quote(fmt.Sprint(value))
+}
+
+func toJson(value interface{}) (string, error) {
+	out, err := json.Marshal(value)
+	return string(out), err
+}
+
+func b64enc(value interface{}) string {
+	return base64.StdEncoding.EncodeToString([]byte(fmt.Sprint(value)))
+}
+
+// required fails rendering with message when value is empty.
+func required(message string, value interface{}) (interface{}, error) {
+	if empty(value) {
+		return nil, errors.New(message)
+	}
+
+	return value, nil
+}
@@ -0,0 +1,239 @@
+package tmpl
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const jobTemplate = `apiVersion: batch/v1
+kind: Job
+metadata:
+  name: {{ .name }}
+spec:
+  template:
+    spec:
+      containers:
+        - name: main
+          image: {{ index . "image" | default "busybox" }}
+          args: [{{ .arg | quote }}]
+`
+
+func TestSecretRefs(t *testing.T) {
+	tests := []struct {
+		name     string
+		contents string
+		refs     []string
+	}{
+		{name: "none", contents: jobTemplate, refs: []string{}},
+		{name: "field", contents: `value: {{ .Secrets.DB_PASSWORD }}`, refs: []string{"DB_PASSWORD"}},
+		{name: "index", contents: `value: {{ index .Secrets "db.password" }}`, refs: []string{"db.password"}},
+		{name: "index of root", contents: `value: {{ index $.Secrets "token" }}`, refs: []string{"token"}},
+		{
+			name:     "sorted without duplicates",
+			contents: `{{ .Secrets.b }} {{ .Secrets.a }} {{ index .Secrets "b" }} {{ .Secrets.a | quote }}`,
+			refs:     []string{"a", "b"},
+		},
+		{name: "other fields", contents: `{{ .SecretsA.b }} {{ .secrets.c }}`, refs: []string{}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if refs := SecretRefs(test.contents); !reflect.DeepEqual(refs, test.refs) {
+				t.Fatalf("expected %v, got %v", test.refs, refs)
+			}
+		})
+	}
+}
+
+func containerField(t *testing.T, job map[string]interface{}, field string) interface{} {
+	spec := job["spec"].(map[string]interface{})["template"].(map[string]interface{})["spec"].(map[string]interface{})
+	container := spec["containers"].([]interface{})[0].(map[string]interface{})
+	return container[field]
+}
+
+func TestRender(t *testing.T) {
+	tests := []struct {
+		name    string
+		params  map[string]interface{}
+		secrets map[string]string
+		extra   string
+		image   string
+		args    []interface{}
+	}{
+		{
+			name:   "params",
+			params: map[string]interface{}{"name": "backup", "image": "alpine:3", "arg": "--all"},
+			image:  "alpine:3",
+			args:   []interface{}{"--all"},
+		},
+		{
+			name:   "optional param",
+			params: map[string]interface{}{"name": "backup", "arg": "--all"},
+			image:  "busybox",
+			args:   []interface{}{"--all"},
+		},
+		{
+			name:   "empty optional param",
+			params: map[string]interface{}{"name": "backup", "image": "", "arg": "--all"},
+			image:  "busybox",
+			args:   []interface{}{"--all"},
+		},
+		{
+			name:   "quoted param",
+			params: map[string]interface{}{"name": "backup", "arg": "a: \"b\"\nc"},
+			image:  "busybox",
+			args:   []interface{}{"a: \"b\"\nc"},
+		},
+		{
+			name:    "secret",
+			params:  map[string]interface{}{"name": "backup", "arg": "--all"},
+			secrets: map[string]string{"token": "{{secrets.token}}"},
+			extra:   "          env: [{name: TOKEN, value: {{ .Secrets.token | quote }}}]\n",
+			image:   "busybox",
+			args:    []interface{}{"--all"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rendered, err := Render(jobTemplate+test.extra, test.params, test.secrets, "")
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if name := rendered.Job["metadata"].(map[string]interface{})["name"]; name != test.params["name"] {
+				t.Errorf("expected name %v, got %v", test.params["name"], name)
+			}
+
+			if image := containerField(t, rendered.Job, "image"); image != test.image {
+				t.Errorf("expected image %q, got %v", test.image, image)
+			}
+
+			if args := containerField(t, rendered.Job, "args"); !reflect.DeepEqual(args, test.args) {
+				t.Errorf("expected args %v, got %v", test.args, args)
+			}
+
+			for name, value := range test.secrets {
+				env := containerField(t, rendered.Job, "env").([]interface{})[0].(map[string]interface{})
+
+				if env["value"] != value {
+					t.Errorf("expected secret %s to render as %q, got %v", name, value, env["value"])
+				}
+			}
+		})
+	}
+}
+
+func TestRenderResources(t *testing.T) {
+	contents := jobTemplate + `---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: {{ .name }}-config
+data:
+  payload: {{ toJson .payload | quote }}
+  encoded: {{ b64enc .name }}
+`
+	params := map[string]interface{}{"name": "backup", "arg": "x", "payload": map[string]interface{}{"a": 1}}
+	rendered, err := Render(contents, params, nil, "")
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(rendered.Resources) != 1 {
+		t.Fatalf("expected 1 resource, got %d", len(rendered.Resources))
+	}
+
+	data := rendered.Resources[0]["data"].(map[string]interface{})
+	expected := map[string]interface{}{"payload": `{"a":1}`, "encoded": "YmFja3Vw"}
+
+	if !reflect.DeepEqual(data, expected) {
+		t.Fatalf("expected %v, got %v", expected, data)
+	}
+}
+
+func TestRenderErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		contents string
+		params   map[string]interface{}
+		line     int
+		rendered bool
+		message  string
+	}{
+		{
+			name:     "missing param",
+			contents: jobTemplate,
+			params:   map[string]interface{}{"arg": "x"},
+			line:     4,
+			message:  "name is not set",
+		},
+		{
+			name:     "missing secret",
+			contents: "name: job\nvalue: {{ .Secrets.token }}\n",
+			params:   map[string]interface{}{},
+			line:     2,
+			message:  "token is not set",
+		},
+		{
+			name:     "syntax",
+			contents: "name: job\nvalue: {{ .name ) }}\n",
+			params:   map[string]interface{}{"name": "x"},
+			line:     2,
+		},
+		{
+			name:     "required",
+			contents: "name: {{ required \"name is needed\" (index . \"name\") }}\n",
+			params:   map[string]interface{}{},
+			line:     1,
+			message:  "name is needed",
+		},
+		{
+			name:     "unknown function",
+			contents: "name: {{ env \"HOME\" }}\n",
+			params:   map[string]interface{}{},
+			line:     1,
+			message:  "function \"env\" not defined",
+		},
+		{
+			name:     "invalid yaml",
+			contents: "a: b\n c: d\n",
+			params:   map[string]interface{}{},
+			rendered: true,
+		},
+		{
+			name:     "two workloads",
+			contents: "name: a\n---\nname: b\n",
+			params:   map[string]interface{}{},
+			rendered: true,
+			message:  "more than one",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := Render(test.contents, test.params, map[string]string{}, "")
+			var renderError *RenderError
+
+			if !errors.As(err, &renderError) {
+				t.Fatalf("expected a RenderError, got %v", err)
+			}
+
+			if renderError.Rendered != test.rendered {
+				t.Errorf("expected rendered %t, got %t", test.rendered, renderError.Rendered)
+			}
+
+			if test.line != 0 && renderError.Line != test.line {
+				t.Errorf("expected line %d, got %d: %v", test.line, renderError.Line, err)
+			}
+
+			if !strings.Contains(renderError.Message, test.message) {
+				t.Errorf("expected a message containing %q, got %q", test.message, renderError.Message)
+			}
+		})
+	}
+}
@@ -0,0 +1,30 @@
+package tmpl
+
+import "fmt"
+
+// TEMPLATE_FILE is rendered into the job manifest in templating mode.
+const TEMPLATE_FILE = "data.yaml.tmpl"
+
+// SECRETS_KEY is the template input holding the application secrets, as
+// {{secrets.NAME}} references resolved when the job runs.
+const SECRETS_KEY = "Secrets"
+
+// RenderError is a template failure pinned to a line of TEMPLATE_FILE, or of
+// the rendered manifest when Rendered is set. Line is 0 when unknown.
+type RenderError struct {
+	Line     int    `json:"line"`
+	Rendered bool   `json:"rendered"`
+	Message  string `json:"message"`
+}
+
+func (e *RenderError) Error() string {
+	if e.Line == 0 {
+		return e.Message
+	}
+
+	if e.Rendered {
+		return fmt.Sprintf("rendered line %d: %s", e.Line, e.Message)
+	}
+
+	return fmt.Sprintf("%s:%d: %s", TEMPLATE_FILE, e.Line, e.Message)
+}
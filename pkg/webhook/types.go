@@ -0,0 +1,46 @@
+package webhook
+
+import "errors"
+
+const (
+	PROVIDER_GITHUB    = "github"
+	PROVIDER_GITLAB    = "gitlab"
+	PROVIDER_GITEA     = "gitea"
+	PROVIDER_BITBUCKET = "bitbucket"
+)
+
+var (
+	ErrUnknownProvider  = errors.New("unknown webhook provider")
+	ErrInvalidSignature = errors.New("invalid webhook signature")
+	ErrIgnoredEvent     = errors.New("event is not a push")
+)
+
+// Push is the provider independent part of a push payload. Refs holds every
+// fully qualified ref that was updated, e.g. refs/heads/main.
+type Push struct {
+	Provider string
+	Refs     []string
+}
+
+type refPayload struct {
+	Ref string `json:"ref"`
+}
+
+type bitbucketCloudPayload struct {
+	Push struct {
+		Changes []struct {
+			New *struct {
+				Type string `json:"type"`
+				Name string `json:"name"`
+			} `json:"new"`
+		} `json:"changes"`
+	} `json:"push"`
+}
+
+type bitbucketServerPayload struct {
+	Changes []struct {
+		Ref struct {
+			Id string `json:"id"`
+		} `json:"ref"`
+	} `json:"changes"`
+}
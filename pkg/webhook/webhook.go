@@ -0,0 +1,164 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+// Parse verifies a push webhook against the repo secret and extracts the
+// updated refs. Signature checks happen before the event type is looked at,
+// so unsigned requests are rejected even when they would be ignored.
+func Parse(header http.Header, body []byte, secret string) (*Push, error) {
+	provider := detectProvider(header)
+
+	if provider == "" {
+		return nil, ErrUnknownProvider
+	}
+
+	if secret == "" || !verify(provider, header, body, secret) {
+		return nil, ErrInvalidSignature
+	}
+
+	if !isPush(provider, header) {
+		return nil, ErrIgnoredEvent
+	}
+
+	refs, err := pushedRefs(provider, header, body)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &Push{Provider: provider, Refs: refs}, nil
+}
+
+// Matches reports whether the push updated the given branch.
+func (p *Push) Matches(branch string) bool {
+	ref := "refs/heads/" + branch
+
+	for _, r := range p.Refs {
+		if r == ref {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Gitea also sends GitHub and Gogs headers, so it has to be checked first.
+func detectProvider(header http.Header) string {
+	switch {
+	case header.Get("X-Gitea-Event") != "":
+		return PROVIDER_GITEA
+	case header.Get("X-Gitlab-Event") != "":
+		return PROVIDER_GITLAB
+	case header.Get("X-GitHub-Event") != "":
+		return PROVIDER_GITHUB
+	case header.Get("X-Event-Key") != "":
+		return PROVIDER_BITBUCKET
+	}
+
+	return ""
+}
+
+func verify(provider string, header http.Header, body []byte, secret string) bool {
+	switch provider {
+	case PROVIDER_GITHUB, PROVIDER_BITBUCKET:
+		signature := header.Get("X-Hub-Signature-256")
+
+		if signature == "" {
+			signature = header.Get("X-Hub-Signature")
+		}
+
+		return verifyHMAC(strings.TrimPrefix(signature, "sha256="), body, secret)
+	case PROVIDER_GITEA:
+		return verifyHMAC(header.Get("X-Gitea-Signature"), body, secret)
+	case PROVIDER_GITLAB:
+		token := header.Get("X-Gitlab-Token")
+		return subtle.ConstantTimeCompare([]byte(token), []byte(secret)) == 1
+	}
+
+	return false
+}
+
+func verifyHMAC(signature string, body []byte, secret string) bool {
+	expected, err := hex.DecodeString(signature)
+
+	if err != nil || len(expected) == 0 {
+		return false
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+
+	return hmac.Equal(mac.Sum(nil), expected)
+}
+
+func isPush(provider string, header http.Header) bool {
+	switch provider {
+	case PROVIDER_GITHUB:
+		return header.Get("X-GitHub-Event") == "push"
+	case PROVIDER_GITEA:
+		return header.Get("X-Gitea-Event") == "push"
+	case PROVIDER_GITLAB:
+		return header.Get("X-Gitlab-Event") == "Push Hook"
+	case PROVIDER_BITBUCKET:
+		event := header.Get("X-Event-Key")
+		return event == "repo:push" || event == "repo:refs_changed"
+	}
+
+	return false
+}
+
+func pushedRefs(provider string, header http.Header, body []byte) ([]string, error) {
+	var refs []string
+
+	if provider != PROVIDER_BITBUCKET {
+		var payload refPayload
+		err := json.Unmarshal(body, &payload)
+
+		if err != nil {
+			return nil, err
+		}
+
+		return append(refs, payload.Ref), nil
+	}
+
+	// Bitbucket Server
+	if header.Get("X-Event-Key") == "repo:refs_changed" {
+		var payload bitbucketServerPayload
+		err := json.Unmarshal(body, &payload)
+
+		if err != nil {
+			return nil, err
+		}
+
+		for _, change := range payload.Changes {
+			refs = append(refs, change.Ref.Id)
+		}
+
+		return refs, nil
+	}
+
+	// Bitbucket Cloud
+	var payload bitbucketCloudPayload
+	err := json.Unmarshal(body, &payload)
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, change := range payload.Push.Changes {
+		// new is null when a branch is deleted
+		if change.New != nil && change.New.Type == "branch" {
+			refs = append(refs, "refs/heads/"+change.New.Name)
+		}
+	}
+
+	return refs, nil
+}
@@ -0,0 +1,191 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+const testSecret = "s3cret"
+
+func sign(body string, secret string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(body))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func headers(pairs ...string) http.Header {
+	header := http.Header{}
+
+	for i := 0; i < len(pairs); i += 2 {
+		header.Set(pairs[i], pairs[i+1])
+	}
+
+	return header
+}
+
+func TestParse(t *testing.T) {
+	pushBody := `{"ref":"refs/heads/main"}`
+	bitbucketServerBody := `{"changes":[{"ref":{"id":"refs/heads/main"}},{"ref":{"id":"refs/tags/v1"}}]}`
+	bitbucketCloudBody := `{"push":{"changes":[{"new":{"type":"branch","name":"main"}},{"new":null},{"new":{"type":"tag","name":"v1"}}]}}`
+
+	tests := []struct {
+		name   string
+		header http.Header
+		body   string
+		secret string
+		refs   []string
+		err    error
+	}{
+		{
+			name:   "github sha256",
+			header: headers("X-GitHub-Event", "push", "X-Hub-Signature-256", "sha256="+sign(pushBody, testSecret)),
+			body:   pushBody,
+			secret: testSecret,
+			refs:   []string{"refs/heads/main"},
+		},
+		{
+			name:   "github wrong secret",
+			header: headers("X-GitHub-Event", "push", "X-Hub-Signature-256", "sha256="+sign(pushBody, "other")),
+			body:   pushBody,
+			secret: testSecret,
+			err:    ErrInvalidSignature,
+		},
+		{
+			name:   "github tampered body",
+			header: headers("X-GitHub-Event", "push", "X-Hub-Signature-256", "sha256="+sign(pushBody, testSecret)),
+			body:   `{"ref":"refs/heads/other"}`,
+			secret: testSecret,
+			err:    ErrInvalidSignature,
+		},
+		{
+			name:   "github unsigned",
+			header: headers("X-GitHub-Event", "push"),
+			body:   pushBody,
+			secret: testSecret,
+			err:    ErrInvalidSignature,
+		},
+		{
+			name:   "github signature not hex",
+			header: headers("X-GitHub-Event", "push", "X-Hub-Signature-256", "sha256=zz"),
+			body:   pushBody,
+			secret: testSecret,
+			err:    ErrInvalidSignature,
+		},
+		{
+			name:   "repo without secret",
+			header: headers("X-GitHub-Event", "push", "X-Hub-Signature-256", "sha256="+sign(pushBody, "")),
+			body:   pushBody,
+			secret: "",
+			err:    ErrInvalidSignature,
+		},
+		{
+			name:   "github signed other event",
+			header: headers("X-GitHub-Event", "ping", "X-Hub-Signature-256", "sha256="+sign(pushBody, testSecret)),
+			body:   pushBody,
+			secret: testSecret,
+			err:    ErrIgnoredEvent,
+		},
+		{
+			name:   "github unsigned other event",
+			header: headers("X-GitHub-Event", "ping"),
+			body:   pushBody,
+			secret: testSecret,
+			err:    ErrInvalidSignature,
+		},
+		{
+			name:   "gitea",
+			header: headers("X-Gitea-Event", "push", "X-GitHub-Event", "push", "X-Gitea-Signature", sign(pushBody, testSecret)),
+			body:   pushBody,
+			secret: testSecret,
+			refs:   []string{"refs/heads/main"},
+		},
+		{
+			name:   "gitea with only the github signature",
+			header: headers("X-Gitea-Event", "push", "X-GitHub-Event", "push", "X-Hub-Signature-256", "sha256="+sign(pushBody, testSecret)),
+			body:   pushBody,
+			secret: testSecret,
+			err:    ErrInvalidSignature,
+		},
+		{
+			name:   "gitlab",
+			header: headers("X-Gitlab-Event", "Push Hook", "X-Gitlab-Token", testSecret),
+			body:   pushBody,
+			secret: testSecret,
+			refs:   []string{"refs/heads/main"},
+		},
+		{
+			name:   "gitlab wrong token",
+			header: headers("X-Gitlab-Event", "Push Hook", "X-Gitlab-Token", "other"),
+			body:   pushBody,
+			secret: testSecret,
+			err:    ErrInvalidSignature,
+		},
+		{
+			name:   "bitbucket server",
+			header: headers("X-Event-Key", "repo:refs_changed", "X-Hub-Signature", "sha256="+sign(bitbucketServerBody, testSecret)),
+			body:   bitbucketServerBody,
+			secret: testSecret,
+			refs:   []string{"refs/heads/main", "refs/tags/v1"},
+		},
+		{
+			name:   "bitbucket cloud",
+			header: headers("X-Event-Key", "repo:push", "X-Hub-Signature", "sha256="+sign(bitbucketCloudBody, testSecret)),
+			body:   bitbucketCloudBody,
+			secret: testSecret,
+			refs:   []string{"refs/heads/main"},
+		},
+		{
+			name:   "unknown provider",
+			header: headers("X-Other-Event", "push"),
+			body:   pushBody,
+			secret: testSecret,
+			err:    ErrUnknownProvider,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			push, err := Parse(test.header, []byte(test.body), test.secret)
+
+			if test.err != nil {
+				if !errors.Is(err, test.err) {
+					t.Fatalf("expected %v, got %v", test.err, err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !reflect.DeepEqual(push.Refs, test.refs) {
+				t.Fatalf("expected refs %v, got %v", test.refs, push.Refs)
+			}
+		})
+	}
+}
+
+func TestPushMatches(t *testing.T) {
+	push := &Push{Refs: []string{"refs/heads/main", "refs/tags/v1"}}
+
+	tests := []struct {
+		branch  string
+		matches bool
+	}{
+		{branch: "main", matches: true},
+		{branch: "v1", matches: false},
+		{branch: "feature", matches: false},
+	}
+
+	for _, test := range tests {
+		if got := push.Matches(test.branch); got != test.matches {
+			t.Errorf("Matches(%q) = %t, expected %t", test.branch, got, test.matches)
+		}
+	}
+}
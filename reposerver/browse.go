@@ -0,0 +1,240 @@
+package reposerver
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	TREE_ENTRY_DIR       = "dir"
+	TREE_ENTRY_FILE      = "file"
+	TREE_ENTRY_SYMLINK   = "symlink"
+	TREE_ENTRY_SUBMODULE = "submodule"
+
+	// Larger files are returned truncated.
+	maxFileSize = 1 << 20
+	// Larger trees are returned truncated.
+	maxTreeEntries = 5000
+	// Git looks for a NUL byte in the first 8000 bytes to detect binaries.
+	binarySniffLength = 8000
+)
+
+func (s RepoService) ListTree(ctx context.Context, request *TreeRequest) (*TreeResponse, error) {
+	release, err := s.readCheckout(ctx, request.RepoId, request.Branch)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer release()
+
+	treePath, err := cleanRepoPath(request.Path)
+
+	if err != nil {
+		return nil, err
+	}
+
+	commit, err := s.resolveCommit(request.RepoId, request.RepoUrl, request.Branch, request.Ref)
+
+	if err != nil {
+		return nil, err
+	}
+
+	tree, err := commit.Tree()
+
+	if err != nil {
+		return nil, err
+	}
+
+	if treePath != "" {
+		tree, err = tree.Tree(treePath)
+
+		if err == object.ErrDirectoryNotFound {
+			return nil, status.Errorf(codes.NotFound, "directory %s not found", treePath)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	resp := TreeResponse{Hash: commit.Hash.String(), Path: treePath}
+
+	for _, entry := range tree.Entries {
+		if len(resp.Entries) == maxTreeEntries {
+			resp.Truncated = true
+			break
+		}
+
+		treeEntry := TreeEntry{
+			Name: entry.Name,
+			Path: path.Join(treePath, entry.Name),
+			Type: entryType(entry.Mode),
+			Hash: entry.Hash.String(),
+		}
+
+		if treeEntry.Type == TREE_ENTRY_FILE {
+			size, err := tree.Size(entry.Name)
+
+			if err != nil {
+				return nil, err
+			}
+
+			treeEntry.Size = size
+		}
+
+		resp.Entries = append(resp.Entries, &treeEntry)
+	}
+
+	return &resp, nil
+}
+
+func (s RepoService) ReadFile(ctx context.Context, request *FileRequest) (*FileResponse, error) {
+	release, err := s.readCheckout(ctx, request.RepoId, request.Branch)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer release()
+
+	filePath, err := cleanRepoPath(request.Path)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if filePath == "" {
+		return nil, status.Error(codes.InvalidArgument, "path is required")
+	}
+
+	commit, err := s.resolveCommit(request.RepoId, request.RepoUrl, request.Branch, request.Ref)
+
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := commit.File(filePath)
+
+	if err == object.ErrFileNotFound {
+		return nil, status.Errorf(codes.NotFound, "file %s not found", filePath)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	reader, err := file.Reader()
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer reader.Close()
+
+	content, err := io.ReadAll(io.LimitReader(reader, maxFileSize))
+
+	if err != nil {
+		return nil, err
+	}
+
+	resp := FileResponse{
+		Hash:      file.Hash.String(),
+		Path:      filePath,
+		Size:      file.Size,
+		Truncated: file.Size > maxFileSize,
+	}
+
+	sniff := content
+
+	if len(sniff) > binarySniffLength {
+		sniff = sniff[:binarySniffLength]
+	}
+
+	if bytes.IndexByte(sniff, 0) != -1 {
+		resp.Binary = true
+		return &resp, nil
+	}
+
+	resp.Content = content
+	return &resp, nil
+}
+
+// resolveCommit opens the checkout of the repo branch and resolves ref, which may be
+// a branch, tag or commit hash. An empty ref resolves to HEAD.
+func (s RepoService) resolveCommit(repoId string, repoUrl string, branch string, ref string) (*object.Commit, error) {
+	repoDir, err := s.checkoutDir(repoId, repoUrl, branch)
+
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	r, err := git.PlainOpen(repoDir)
+
+	if err == git.ErrRepositoryNotExists {
+		return nil, status.Error(codes.FailedPrecondition, "repository has not been synced yet")
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if ref == "" {
+		ref = string(plumbing.HEAD)
+	}
+
+	hash, err := r.ResolveRevision(plumbing.Revision(ref))
+
+	if err != nil {
+		// single branch clones only know the remote tracking refs
+		hash, err = r.ResolveRevision(plumbing.Revision("origin/" + ref))
+	}
+
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "ref %s not found", ref)
+	}
+
+	return r.CommitObject(*hash)
+}
+
+// cleanRepoPath normalizes a user supplied path relative to the repository
+// root and rejects any path that would leave it.
+func cleanRepoPath(p string) (string, error) {
+	p = strings.ReplaceAll(p, "\\", "/")
+
+	if strings.ContainsRune(p, 0) {
+		return "", status.Error(codes.InvalidArgument, "invalid path")
+	}
+
+	for _, segment := range strings.Split(p, "/") {
+		if segment == ".." {
+			return "", status.Error(codes.InvalidArgument, fmt.Sprintf("path %s is outside of the repository", p))
+		}
+	}
+
+	return strings.Trim(path.Clean("/"+p), "/"), nil
+}
+
+func entryType(mode filemode.FileMode) string {
+	switch mode {
+	case filemode.Dir:
+		return TREE_ENTRY_DIR
+	case filemode.Symlink:
+		return TREE_ENTRY_SYMLINK
+	case filemode.Submodule:
+		return TREE_ENTRY_SUBMODULE
+	}
+
+	return TREE_ENTRY_FILE
+}
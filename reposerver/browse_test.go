@@ -0,0 +1,55 @@
+package reposerver
+
+import (
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestCleanRepoPath(t *testing.T) {
+	tests := []struct {
+		path    string
+		cleaned string
+		err     bool
+	}{
+		{path: "", cleaned: ""},
+		{path: "/", cleaned: ""},
+		{path: ".", cleaned: ""},
+		{path: "jobs/backup", cleaned: "jobs/backup"},
+		{path: "/jobs/backup/", cleaned: "jobs/backup"},
+		{path: "jobs//backup/./data.yaml", cleaned: "jobs/backup/data.yaml"},
+		{path: `jobs\backup`, cleaned: "jobs/backup"},
+		{path: "..foo/bar..", cleaned: "..foo/bar.."},
+		{path: "..", err: true},
+		{path: "../etc/passwd", err: true},
+		{path: "jobs/../../etc", err: true},
+		{path: "jobs/../backup", err: true},
+		{path: "/..", err: true},
+		{path: `..\etc`, err: true},
+		{path: `jobs\..\..\etc`, err: true},
+		{path: "jobs/\x00/backup", err: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.path, func(t *testing.T) {
+			cleaned, err := cleanRepoPath(test.path)
+
+			if test.err {
+				if status.Code(err) != codes.InvalidArgument {
+					t.Fatalf("expected InvalidArgument, got %q, %v", cleaned, err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if cleaned != test.cleaned {
+				t.Fatalf("expected %q, got %q", test.cleaned, cleaned)
+			}
+		})
+	}
+}
@@ -0,0 +1,142 @@
+package reposerver
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	lru "github.com/hashicorp/golang-lru/v2"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	DEFAULT_MANIFEST_CACHE_SIZE = 1000
+	// How often the cache counters are logged, when the cache was used.
+	cacheStatsInterval = 10 * time.Minute
+)
+
+// manifestKey identifies the manifests of an application at a commit. The
+// commit is part of the key, so entries of a checkout are never served once
+// a sync moved its HEAD and age out of the LRU instead.
+type manifestKey struct {
+	checkout string
+	commit   string
+	path     string
+	overlay  string
+	workload string
+}
+
+// manifestCache keeps parsed manifests of the checkouts, bounded by an LRU.
+type manifestCache struct {
+	entries *lru.Cache[manifestKey, *ManifestsResponse]
+	hits    atomic.Uint64
+	misses  atomic.Uint64
+
+	mu sync.Mutex
+	// heads is the HEAD commit of each checkout, updated after every sync so
+	// lookups do not open the repo.
+	heads map[string]string
+}
+
+// newManifestCache returns nil when size is not positive, which disables
+// caching.
+func newManifestCache(size int) *manifestCache {
+	if size <= 0 {
+		log.Infoln("Manifest cache disabled")
+		return nil
+	}
+
+	entries, err := lru.New[manifestKey, *ManifestsResponse](size)
+
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	return &manifestCache{entries: entries, heads: make(map[string]string)}
+}
+
+// key returns the cache key of a manifests request, false when the checkout
+// has no commit to key it by.
+func (c *manifestCache) key(repoRoot string, request *ManifestsRequest) (manifestKey, bool) {
+	checkout := checkoutKey(request.Path)
+	commit := c.head(repoRoot, checkout)
+
+	if commit == "" {
+		return manifestKey{}, false
+	}
+
+	return manifestKey{
+		checkout: checkout,
+		commit:   commit,
+		path:     request.Path,
+		overlay:  request.Overlay,
+		workload: request.Workload,
+	}, true
+}
+
+func (c *manifestCache) head(repoRoot string, checkout string) string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	commit, ok := c.heads[checkout]
+
+	if !ok {
+		commit = headHash(repoRoot + "/" + checkout)
+		c.heads[checkout] = commit
+	}
+
+	return commit
+}
+
+// setHead records the HEAD of a checkout after a sync.
+func (c *manifestCache) setHead(checkout string, commit string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.heads[checkout] != commit {
+		log.Debugf("Manifest cache of %s moved to %s", checkout, commit)
+	}
+
+	c.heads[checkout] = commit
+}
+
+// forget drops the HEAD of a removed checkout. Its entries are no longer
+// looked up and age out of the LRU.
+func (c *manifestCache) forget(checkout string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.heads, checkout)
+}
+
+func (c *manifestCache) get(key manifestKey) (*ManifestsResponse, bool) {
+	resp, ok := c.entries.Get(key)
+
+	if ok {
+		c.hits.Add(1)
+	} else {
+		c.misses.Add(1)
+	}
+
+	return resp, ok
+}
+
+func (c *manifestCache) add(key manifestKey, resp *ManifestsResponse) {
+	c.entries.Add(key, resp)
+}
+
+// logStats logs the hit and miss counters every interval in which the cache
+// was used.
+func (c *manifestCache) logStats() {
+	var lastHits, lastMisses uint64
+
+	for range time.Tick(cacheStatsInterval) {
+		hits, misses := c.hits.Load(), c.misses.Load()
+
+		if hits == lastHits && misses == lastMisses {
+			continue
+		}
+
+		log.Infof("Manifest cache: %d hits, %d misses, %.1f%% hit rate, %d entries", hits, misses, 100*float64(hits)/float64(hits+misses), c.entries.Len())
+		lastHits, lastMisses = hits, misses
+	}
+}
@@ -0,0 +1,326 @@
+package reposerver
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	gitssh "github.com/go-git/go-git/v5/plumbing/transport/ssh"
+	"github.com/go-git/go-git/v5/storage/memory"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// SSH_KEYS_KEY is the secret the private keys are encrypted with.
+	SSH_KEYS_KEY = "SSH_KEYS_KEY"
+	// PENDING_PRIVATE_KEY holds a rotated key until it is confirmed to work,
+	// the previous key keeps being used for syncs meanwhile.
+	PENDING_PRIVATE_KEY = "private_key.pending"
+	SEALED_KEY_HEADER   = "kubefill-sealed-key-v1\n"
+)
+
+var errNoKeysKey = errors.New(SSH_KEYS_KEY + " is not set, private keys can not be encrypted")
+
+// GenerateDeployKey creates an ed25519 key pair for a repo and returns the
+// public key to register with the git host. A repo which already has a key
+// gets the new one as pending until ConfirmDeployKey.
+func (s RepoService) GenerateDeployKey(_ context.Context, request *GenerateDeployKeyRequest) (*GenerateDeployKeyResponse, error) {
+	if err := validateRepoId(request.RepoId); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := ssh.MarshalPrivateKey(privateKey, "kubefill-repo-"+request.RepoId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	sealed, err := sealPrivateKey(pem.EncodeToMemory(block))
+
+	if err != nil {
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	}
+
+	sshPublicKey, err := ssh.NewPublicKey(publicKey)
+
+	if err != nil {
+		return nil, err
+	}
+
+	keyDir, err := s.keyDir(request.RepoId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	keyFile := PRIVATE_KEY
+	_, err = os.Stat(filepath.Join(keyDir, PRIVATE_KEY))
+	pending := err == nil
+
+	if pending {
+		keyFile = PENDING_PRIVATE_KEY
+	}
+
+	err = writePrivateKey(filepath.Join(keyDir, keyFile), sealed)
+
+	if err != nil {
+		return nil, err
+	}
+
+	authorizedKey := strings.TrimSpace(string(ssh.MarshalAuthorizedKey(sshPublicKey))) + " kubefill-repo-" + request.RepoId
+	log.Infof("Generated deploy key %s for repo %s, pending: %t", ssh.FingerprintSHA256(sshPublicKey), request.RepoId, pending)
+
+	return &GenerateDeployKeyResponse{PublicKey: authorizedKey, Pending: pending}, nil
+}
+
+// ConfirmDeployKey checks that the pending key of a repo can read the remote
+// and replaces the previous key with it.
+func (s RepoService) ConfirmDeployKey(ctx context.Context, request *ConfirmDeployKeyRequest) (*ConfirmDeployKeyResponse, error) {
+	if err := validateRepoId(request.RepoId); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	keyDir := filepath.Join(s.sshRoot, request.RepoId)
+	pendingPath := filepath.Join(keyDir, PENDING_PRIVATE_KEY)
+	auth, err := loadPrivateKey(pendingPath)
+
+	if os.IsNotExist(err) {
+		return nil, status.Error(codes.FailedPrecondition, "the repo has no pending deploy key")
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	gitUrl, err := s.parseRemote(request.Repo)
+
+	if err != nil {
+		return nil, err
+	}
+
+	trustHost(gitUrl)
+	remote := git.NewRemote(memory.NewStorage(), &config.RemoteConfig{Name: "origin", URLs: []string{request.Repo}})
+	_, err = remote.ListContext(ctx, &git.ListOptions{Auth: auth})
+
+	if err != nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "the new deploy key can not read %s: %v", request.Repo, err)
+	}
+
+	err = os.Rename(pendingPath, filepath.Join(keyDir, PRIVATE_KEY))
+
+	if err != nil {
+		return nil, err
+	}
+
+	authorizedKey := strings.TrimSpace(string(ssh.MarshalAuthorizedKey(auth.Signer.PublicKey()))) + " kubefill-repo-" + request.RepoId
+	log.Infof("Confirmed deploy key %s for repo %s", ssh.FingerprintSHA256(auth.Signer.PublicKey()), request.RepoId)
+
+	return &ConfirmDeployKeyResponse{PublicKey: authorizedKey}, nil
+}
+
+// keyDir returns the directory of a repo's keys, created readable by the
+// reposerver only.
+func (s RepoService) keyDir(repoId string) (string, error) {
+	if err := validateRepoId(repoId); err != nil {
+		return "", status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	keyDir := filepath.Join(s.sshRoot, repoId)
+	err := os.MkdirAll(keyDir, 0700)
+
+	if err != nil {
+		return "", err
+	}
+
+	return keyDir, os.Chmod(keyDir, 0700)
+}
+
+// writePrivateKey replaces a key file atomically, with 0600 permissions.
+func writePrivateKey(keyPath string, contents []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(keyPath), filepath.Base(keyPath))
+
+	if err != nil {
+		return err
+	}
+
+	defer os.Remove(tmp.Name())
+
+	_, err = tmp.Write(contents)
+	closeErr := tmp.Close()
+
+	if err != nil {
+		return err
+	}
+
+	if closeErr != nil {
+		return closeErr
+	}
+
+	err = os.Chmod(tmp.Name(), 0600)
+
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), keyPath)
+}
+
+// loadPrivateKey reads a key file, decrypting sealed keys.
+func loadPrivateKey(keyPath string) (*gitssh.PublicKeys, error) {
+	contents, err := os.ReadFile(keyPath)
+
+	if err != nil {
+		return nil, err
+	}
+
+	contents, err = openPrivateKey(contents)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt %s: %w", keyPath, err)
+	}
+
+	return gitssh.NewPublicKeys("git", contents, "")
+}
+
+func keysCipher() (cipher.AEAD, error) {
+	secret := os.Getenv(SSH_KEYS_KEY)
+
+	if secret == "" {
+		return nil, errNoKeysKey
+	}
+
+	key := sha256.Sum256([]byte(secret))
+	block, err := aes.NewCipher(key[:])
+
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
+
+// sealPrivateKey encrypts a private key with AES-GCM under SSH_KEYS_KEY.
+func sealPrivateKey(contents []byte) ([]byte, error) {
+	aead, err := keysCipher()
+
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	sealed := append([]byte(SEALED_KEY_HEADER), nonce...)
+	return aead.Seal(sealed, nonce, contents, []byte(SEALED_KEY_HEADER)), nil
+}
+
+// openPrivateKey decrypts a sealed private key. Keys saved before keys were
+// encrypted are returned as they are.
+func openPrivateKey(contents []byte) ([]byte, error) {
+	if !bytes.HasPrefix(contents, []byte(SEALED_KEY_HEADER)) {
+		return contents, nil
+	}
+
+	aead, err := keysCipher()
+
+	if err != nil {
+		return nil, err
+	}
+
+	sealed := contents[len(SEALED_KEY_HEADER):]
+
+	if len(sealed) < aead.NonceSize() {
+		return nil, errors.New("sealed key is truncated")
+	}
+
+	return aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], []byte(SEALED_KEY_HEADER))
+}
+
+// sealStoredKeys restricts the permissions of the stored private keys and
+// encrypts the ones saved in plain text, when SSH_KEYS_KEY is set.
+func sealStoredKeys(sshRoot string) error {
+	entries, err := os.ReadDir(sshRoot)
+
+	if err != nil {
+		return err
+	}
+
+	_, err = keysCipher()
+	canSeal := err == nil
+
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+
+		keyDir := filepath.Join(sshRoot, entry.Name())
+
+		for _, keyFile := range []string{PRIVATE_KEY, PENDING_PRIVATE_KEY} {
+			keyPath := filepath.Join(keyDir, keyFile)
+			contents, err := os.ReadFile(keyPath)
+
+			if os.IsNotExist(err) {
+				continue
+			}
+
+			if err != nil {
+				return err
+			}
+
+			if err := os.Chmod(keyDir, 0700); err != nil {
+				return err
+			}
+
+			if !canSeal || bytes.HasPrefix(contents, []byte(SEALED_KEY_HEADER)) {
+				if err := os.Chmod(keyPath, 0600); err != nil {
+					return err
+				}
+
+				continue
+			}
+
+			log.Infof("Encrypting the private key of repo %s", entry.Name())
+			contents, err = sealPrivateKey(contents)
+
+			if err != nil {
+				return err
+			}
+
+			err = writePrivateKey(keyPath, contents)
+
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	if !canSeal {
+		log.Warnf("%s is not set, private keys are stored unencrypted", SSH_KEYS_KEY)
+	}
+
+	return nil
+}
@@ -0,0 +1,158 @@
+package reposerver
+
+import (
+	"io"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/go-git/go-git/v5/plumbing/object"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/net/context"
+)
+
+// DiscoverApplications walks the tree at the requested ref and returns every
+// directory that holds a data, schema and uischema file which all parse. A
+// file of such a set which does not parse is reported as an issue, and the
+// requested paths which are directories of the tree as present.
+func (s RepoService) DiscoverApplications(ctx context.Context, request *DiscoverRequest) (*DiscoverResponse, error) {
+	release, err := s.readCheckout(ctx, request.RepoId, request.Branch)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer release()
+
+	commit, err := s.resolveCommit(request.RepoId, request.RepoUrl, request.Branch, request.Ref)
+
+	if err != nil {
+		return nil, err
+	}
+
+	candidates := make(map[string]map[string]*object.File)
+	requested := make(map[string]bool)
+	present := make(map[string]bool)
+
+	for _, p := range request.Paths {
+		requested[path.Clean(p)] = true
+	}
+
+	files, err := commit.Files()
+
+	if err != nil {
+		return nil, err
+	}
+
+	err = files.ForEach(func(file *object.File) error {
+		if !file.Mode.IsFile() {
+			return nil
+		}
+
+		for dir := path.Dir(file.Name); len(requested) > 0; dir = path.Dir(dir) {
+			if requested[dir] {
+				present[dir] = true
+			}
+
+			if dir == "." {
+				break
+			}
+		}
+
+		base := path.Base(file.Name)
+		ext := path.Ext(base)
+
+		if !contains(validManifestExt, ext) || !contains(manifestFiles, strings.TrimSuffix(base, ext)) {
+			return nil
+		}
+
+		dir := path.Dir(file.Name)
+
+		if candidates[dir] == nil {
+			candidates[dir] = make(map[string]*object.File)
+		}
+
+		candidates[dir][strings.TrimSuffix(base, ext)] = file
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	resp := DiscoverResponse{Hash: commit.Hash.String()}
+
+	for dir, manifests := range candidates {
+		valid, issues := validManifestSet(manifests)
+		resp.Issues = append(resp.Issues, issues...)
+
+		if !valid {
+			continue
+		}
+
+		name := path.Base(dir)
+		appPath := dir
+
+		if dir == "." {
+			if gitUrl, err := ParseGitURL(request.RepoUrl); err == nil {
+				name = gitUrl.Name()
+			}
+			appPath = ""
+		}
+
+		resp.Applications = append(resp.Applications, &DiscoveredApplication{Name: name, Path: appPath})
+	}
+
+	sort.Slice(resp.Applications, func(i, j int) bool {
+		return resp.Applications[i].Path < resp.Applications[j].Path
+	})
+
+	sort.Slice(resp.Issues, func(i, j int) bool {
+		return resp.Issues[i].File < resp.Issues[j].File
+	})
+
+	for _, p := range request.Paths {
+		if present[path.Clean(p)] {
+			resp.Present = append(resp.Present, p)
+		}
+	}
+
+	return &resp, nil
+}
+
+// validManifestSet reports whether manifests hold every manifest file and
+// all of them parse. A directory missing one is no application and has no
+// issues, the files of a complete set which fail are issues.
+func validManifestSet(manifests map[string]*object.File) (bool, []*ManifestIssue) {
+	for _, name := range manifestFiles {
+		if _, ok := manifests[name]; !ok {
+			return false, nil
+		}
+	}
+
+	var issues []*ManifestIssue
+
+	for _, name := range manifestFiles {
+		file := manifests[name]
+		reader, err := file.Reader()
+
+		if err != nil {
+			issues = append(issues, &ManifestIssue{File: file.Name, Severity: SEVERITY_ERROR, Message: err.Error()})
+			continue
+		}
+
+		contents, err := io.ReadAll(io.LimitReader(reader, maxFileSize))
+		reader.Close()
+
+		if err == nil {
+			_, err = parseManifest(path.Base(file.Name), contents, "")
+		}
+
+		if err != nil {
+			log.Infof("skipping %s: %v", file.Name, err)
+			issues = append(issues, &ManifestIssue{File: file.Name, Severity: SEVERITY_ERROR, Message: err.Error()})
+		}
+	}
+
+	return len(issues) == 0, issues
+}
@@ -0,0 +1,257 @@
+package reposerver
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	SCHEME_SSH   = "ssh"
+	SCHEME_HTTP  = "http"
+	SCHEME_HTTPS = "https"
+	SCHEME_GIT   = "git"
+	SCHEME_FILE  = "file"
+)
+
+var (
+	// scpLikeUrl matches user@host:path remotes. Like go-git it accepts a port
+	// in front of the path, user@host:2222/owner/repo.
+	scpLikeUrl = regexp.MustCompile(`^(?:([^@/\s]+)@)?([^:/\s]+):(?:([0-9]{1,5})/)?(.+)$`)
+
+	defaultPorts = map[string]string{
+		SCHEME_SSH:   "22",
+		SCHEME_HTTP:  "80",
+		SCHEME_HTTPS: "443",
+		SCHEME_GIT:   "9418",
+	}
+)
+
+// GitURL is a parsed git remote. Scheme is ssh for ssh:// and scp like
+// user@host:path remotes, and file for file:// remotes and local paths.
+type GitURL struct {
+	Scheme string
+	User   string
+	Host   string
+	Port   string
+	Path   string
+}
+
+func ParseGitURL(raw string) (GitURL, error) {
+	raw = strings.TrimSpace(raw)
+
+	if raw == "" {
+		return GitURL{}, errors.New("empty git URL")
+	}
+
+	if strings.Contains(raw, "://") {
+		u, err := url.Parse(raw)
+
+		if err != nil {
+			return GitURL{}, fmt.Errorf("invalid git URL %s: %w", raw, err)
+		}
+
+		gitUrl := GitURL{Scheme: strings.ToLower(u.Scheme), Host: u.Hostname(), Port: u.Port(), Path: u.Path}
+
+		if u.User != nil {
+			gitUrl.User = u.User.Username()
+		}
+
+		switch gitUrl.Scheme {
+		case "git+ssh", "ssh+git":
+			gitUrl.Scheme = SCHEME_SSH
+		case SCHEME_SSH, SCHEME_HTTP, SCHEME_HTTPS, SCHEME_GIT:
+		case SCHEME_FILE:
+			if gitUrl.Host != "" && gitUrl.Host != "localhost" {
+				return GitURL{}, fmt.Errorf("file URL %s must not have a host", raw)
+			}
+
+			gitUrl.Host = ""
+		default:
+			return GitURL{}, fmt.Errorf("unsupported git URL scheme %s", u.Scheme)
+		}
+
+		if gitUrl.Scheme != SCHEME_FILE && gitUrl.Host == "" {
+			return GitURL{}, fmt.Errorf("git URL %s has no host", raw)
+		}
+
+		if gitUrl.Port == "" {
+			gitUrl.Port = defaultPorts[gitUrl.Scheme]
+		}
+
+		if strings.Trim(gitUrl.Path, "/") == "" {
+			return GitURL{}, fmt.Errorf("git URL %s has no repository path", raw)
+		}
+
+		return gitUrl, nil
+	}
+
+	if filepath.IsAbs(raw) {
+		return GitURL{Scheme: SCHEME_FILE, Path: raw}, nil
+	}
+
+	match := scpLikeUrl.FindStringSubmatch(raw)
+
+	if match == nil {
+		return GitURL{}, fmt.Errorf("unsupported git URL %s", raw)
+	}
+
+	gitUrl := GitURL{Scheme: SCHEME_SSH, User: match[1], Host: match[2], Port: match[3], Path: match[4]}
+
+	if gitUrl.Port == "" {
+		gitUrl.Port = defaultPorts[SCHEME_SSH]
+	}
+
+	return gitUrl, nil
+}
+
+func (u GitURL) IsSSH() bool {
+	return u.Scheme == SCHEME_SSH
+}
+
+func (u GitURL) IsLocal() bool {
+	return u.Scheme == SCHEME_FILE
+}
+
+// RepoPath is the path of the repo on its host, without leading slash.
+func (u GitURL) RepoPath() string {
+	return strings.TrimPrefix(u.Path, "/")
+}
+
+// Name is the last path element without the .git suffix.
+func (u GitURL) Name() string {
+	return strings.TrimSuffix(path.Base(strings.TrimRight(u.Path, "/")), ".git")
+}
+
+// Owner is the path element before the name, usually the user or group.
+func (u GitURL) Owner() string {
+	return path.Base(path.Dir(strings.TrimRight(u.Path, "/")))
+}
+
+// checkoutPath is where a repo branch is checked out relative to the repo
+// root. Checkouts are keyed by repo ID and branch, so repos sharing a name or
+// a URL never share a working tree.
+func checkoutPath(repoId string, branch string) (string, error) {
+	if err := validateRepoId(repoId); err != nil {
+		return "", err
+	}
+
+	if err := ValidateBranch(branch); err != nil {
+		return "", err
+	}
+
+	return filepath.Join(repoId, url.PathEscape(branch)), nil
+}
+
+// ValidateBranch checks that branch is a valid git branch name, which also
+// keeps "." and ".." out of checkout paths.
+func ValidateBranch(branch string) error {
+	if branch == "" {
+		return errors.New("branch is required")
+	}
+
+	if plumbing.NewBranchReferenceName(branch).Validate() != nil {
+		return fmt.Errorf("invalid branch name %q", branch)
+	}
+
+	return nil
+}
+
+// parseRemote parses a remote the reposerver clones or lists. Local remotes
+// read the filesystem of the reposerver, so they are refused unless
+// allowLocalRemotes is set.
+func (s RepoService) parseRemote(raw string) (GitURL, error) {
+	gitUrl, err := ParseGitURL(raw)
+
+	if err != nil {
+		return GitURL{}, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	if gitUrl.IsLocal() && !s.allowLocalRemotes {
+		return GitURL{}, status.Errorf(codes.InvalidArgument, "local git URL %s is not allowed", raw)
+	}
+
+	return gitUrl, nil
+}
+
+// validateRepoId checks that a repo ID names a single directory of the repo
+// root.
+func validateRepoId(repoId string) error {
+	if repoId == "" || strings.ContainsAny(repoId, `/\`) || repoId == "." || repoId == ".." {
+		return fmt.Errorf("invalid repo id %q", repoId)
+	}
+
+	return nil
+}
+
+// checkoutDir returns the absolute checkout directory of a repo branch. A
+// checkout left under the former owner-name layout is moved there first.
+func (s RepoService) checkoutDir(repoId string, repoUrl string, branch string) (string, error) {
+	relPath, err := checkoutPath(repoId, branch)
+
+	if err != nil {
+		return "", err
+	}
+
+	repoDir := filepath.Join(s.repoRoot, relPath)
+
+	if _, err := os.Stat(repoDir); os.IsNotExist(err) {
+		s.migrateCheckout(repoUrl, branch, repoDir)
+	}
+
+	return repoDir, nil
+}
+
+// migrateCheckout moves a checkout of the owner-name layout to repoDir when
+// it is a clone of the same URL and branch. Other repos which shared it clone
+// again.
+func (s RepoService) migrateCheckout(repoUrl string, branch string, repoDir string) {
+	gitUrl, err := ParseGitURL(repoUrl)
+
+	if err != nil {
+		return
+	}
+
+	legacyDir := filepath.Join(s.repoRoot, fmt.Sprintf("%s-%s", gitUrl.Owner(), gitUrl.Name()))
+	r, err := git.PlainOpen(legacyDir)
+
+	if err != nil {
+		return
+	}
+
+	remote, err := r.Remote("origin")
+
+	if err != nil || len(remote.Config().URLs) == 0 || remote.Config().URLs[0] != repoUrl {
+		return
+	}
+
+	head, err := r.Head()
+
+	if err != nil || head.Name().Short() != branch {
+		return
+	}
+
+	err = os.MkdirAll(filepath.Dir(repoDir), os.ModePerm)
+
+	if err == nil {
+		err = os.Rename(legacyDir, repoDir)
+	}
+
+	if err != nil {
+		log.Errorf("failed to move checkout %s to %s: %v", legacyDir, repoDir, err)
+		return
+	}
+
+	log.Infof("Moved checkout %s to %s", legacyDir, repoDir)
+}
@@ -0,0 +1,223 @@
+package reposerver
+
+import (
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestParseGitURL(t *testing.T) {
+	tests := []struct {
+		raw   string
+		url   GitURL
+		owner string
+		name  string
+		err   bool
+	}{
+		{
+			raw:   "https://github.com/kubefill/kubefill.git",
+			url:   GitURL{Scheme: SCHEME_HTTPS, Host: "github.com", Port: "443", Path: "/kubefill/kubefill.git"},
+			owner: "kubefill",
+			name:  "kubefill",
+		},
+		{
+			raw:   "http://user@git.example.com:8080/group/sub/repo",
+			url:   GitURL{Scheme: SCHEME_HTTP, User: "user", Host: "git.example.com", Port: "8080", Path: "/group/sub/repo"},
+			owner: "sub",
+			name:  "repo",
+		},
+		{
+			raw:   "ssh://git@github.com/kubefill/kubefill.git",
+			url:   GitURL{Scheme: SCHEME_SSH, User: "git", Host: "github.com", Port: "22", Path: "/kubefill/kubefill.git"},
+			owner: "kubefill",
+			name:  "kubefill",
+		},
+		{
+			raw:   "git+ssh://git@github.com:2222/kubefill/kubefill",
+			url:   GitURL{Scheme: SCHEME_SSH, User: "git", Host: "github.com", Port: "2222", Path: "/kubefill/kubefill"},
+			owner: "kubefill",
+			name:  "kubefill",
+		},
+		{
+			raw:   "git://example.com/repo.git",
+			url:   GitURL{Scheme: SCHEME_GIT, Host: "example.com", Port: "9418", Path: "/repo.git"},
+			owner: "/",
+			name:  "repo",
+		},
+		{
+			raw:   "git@github.com:kubefill/kubefill.git",
+			url:   GitURL{Scheme: SCHEME_SSH, User: "git", Host: "github.com", Port: "22", Path: "kubefill/kubefill.git"},
+			owner: "kubefill",
+			name:  "kubefill",
+		},
+		{
+			raw:   "git@github.com:2222/kubefill/kubefill.git",
+			url:   GitURL{Scheme: SCHEME_SSH, User: "git", Host: "github.com", Port: "2222", Path: "kubefill/kubefill.git"},
+			owner: "kubefill",
+			name:  "kubefill",
+		},
+		{
+			raw:   "  github.com:kubefill/kubefill  ",
+			url:   GitURL{Scheme: SCHEME_SSH, Host: "github.com", Port: "22", Path: "kubefill/kubefill"},
+			owner: "kubefill",
+			name:  "kubefill",
+		},
+		{
+			raw:   "file:///srv/git/repo.git",
+			url:   GitURL{Scheme: SCHEME_FILE, Path: "/srv/git/repo.git"},
+			owner: "git",
+			name:  "repo",
+		},
+		{
+			raw:   "file://localhost/srv/git/repo.git",
+			url:   GitURL{Scheme: SCHEME_FILE, Path: "/srv/git/repo.git"},
+			owner: "git",
+			name:  "repo",
+		},
+		{
+			raw:   "/srv/git/repo",
+			url:   GitURL{Scheme: SCHEME_FILE, Path: "/srv/git/repo"},
+			owner: "git",
+			name:  "repo",
+		},
+		{raw: "", err: true},
+		{raw: "   ", err: true},
+		{raw: "ftp://example.com/repo.git", err: true},
+		{raw: "file://example.com/srv/repo.git", err: true},
+		{raw: "https:///repo.git", err: true},
+		{raw: "https://github.com/", err: true},
+		{raw: "https://github.com", err: true},
+		{raw: "relative/path", err: true},
+		{raw: "https://github.com/%zz", err: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.raw, func(t *testing.T) {
+			gitUrl, err := ParseGitURL(test.raw)
+
+			if test.err {
+				if err == nil {
+					t.Fatalf("expected an error, got %+v", gitUrl)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if gitUrl != test.url {
+				t.Fatalf("expected %+v, got %+v", test.url, gitUrl)
+			}
+
+			if owner := gitUrl.Owner(); owner != test.owner {
+				t.Errorf("expected owner %q, got %q", test.owner, owner)
+			}
+
+			if name := gitUrl.Name(); name != test.name {
+				t.Errorf("expected name %q, got %q", test.name, name)
+			}
+		})
+	}
+}
+
+func TestParseRemote(t *testing.T) {
+	tests := []struct {
+		raw   string
+		local bool
+		code  codes.Code
+	}{
+		{raw: "https://github.com/kubefill/kubefill.git", code: codes.OK},
+		{raw: "git@github.com:kubefill/kubefill.git", code: codes.OK},
+		{raw: "/srv/git/repo", code: codes.InvalidArgument},
+		{raw: "file:///srv/git/repo", code: codes.InvalidArgument},
+		{raw: "/srv/git/repo", local: true, code: codes.OK},
+		{raw: "file:///srv/git/repo", local: true, code: codes.OK},
+		{raw: "ftp://example.com/repo", local: true, code: codes.InvalidArgument},
+	}
+
+	for _, test := range tests {
+		s := RepoService{allowLocalRemotes: test.local}
+		_, err := s.parseRemote(test.raw)
+
+		if code := status.Code(err); code != test.code {
+			t.Errorf("parseRemote(%q) with local remotes %t: expected %s, got %v", test.raw, test.local, test.code, err)
+		}
+	}
+}
+
+func TestValidateRepoId(t *testing.T) {
+	tests := []struct {
+		repoId string
+		valid  bool
+	}{
+		{repoId: "1", valid: true},
+		{repoId: "42", valid: true},
+		{repoId: "", valid: false},
+		{repoId: ".", valid: false},
+		{repoId: "..", valid: false},
+		{repoId: "../1", valid: false},
+		{repoId: "1/2", valid: false},
+		{repoId: `1\2`, valid: false},
+		{repoId: "/1", valid: false},
+	}
+
+	for _, test := range tests {
+		if err := validateRepoId(test.repoId); (err == nil) != test.valid {
+			t.Errorf("validateRepoId(%q) = %v, expected valid %t", test.repoId, err, test.valid)
+		}
+	}
+}
+
+func TestValidateBranch(t *testing.T) {
+	tests := []struct {
+		branch string
+		valid  bool
+	}{
+		{branch: "main", valid: true},
+		{branch: "feature/login", valid: true},
+		{branch: "release-1.2", valid: true},
+		{branch: "", valid: false},
+		{branch: ".", valid: false},
+		{branch: "..", valid: false},
+		{branch: "../main", valid: false},
+		{branch: "feature/../main", valid: false},
+		{branch: "/main", valid: false},
+		{branch: "main/", valid: false},
+		{branch: "main.lock", valid: false},
+		{branch: "a b", valid: false},
+		{branch: "a~b", valid: false},
+		{branch: "a:b", valid: false},
+		{branch: "@{1}", valid: false},
+	}
+
+	for _, test := range tests {
+		if err := ValidateBranch(test.branch); (err == nil) != test.valid {
+			t.Errorf("ValidateBranch(%q) = %v, expected valid %t", test.branch, err, test.valid)
+		}
+	}
+}
+
+func TestCheckoutPath(t *testing.T) {
+	tests := []struct {
+		repoId string
+		branch string
+		path   string
+		err    bool
+	}{
+		{repoId: "1", branch: "main", path: "1/main"},
+		{repoId: "1", branch: "feature/login", path: "1/feature%2Flogin"},
+		{repoId: "..", branch: "main", err: true},
+		{repoId: "1", branch: "..", err: true},
+	}
+
+	for _, test := range tests {
+		p, err := checkoutPath(test.repoId, test.branch)
+
+		if (err != nil) != test.err || p != test.path {
+			t.Errorf("checkoutPath(%q, %q) = %q, %v, expected %q", test.repoId, test.branch, p, err, test.path)
+		}
+	}
+}
@@ -0,0 +1,192 @@
+package reposerver
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	manifestPkg "github.com/kubefill/kubefill/pkg/manifest"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	structpb "google.golang.org/protobuf/types/known/structpb"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/engine"
+)
+
+const (
+	MANIFEST_TYPE_STATIC = "static"
+	MANIFEST_TYPE_HELM   = "helm"
+)
+
+const defaultNamespace = "default"
+
+// isChart reports whether dir holds a Helm chart.
+func isChart(dir string) bool {
+	ok, _ := chartutil.IsChartDir(dir)
+	return ok
+}
+
+// getChartManifests builds the form manifests of a chart: the values schema
+// drives the form, values.yaml provides its defaults and an optional uischema
+// file next to Chart.yaml customises it.
+func getChartManifests(dir string) (*ManifestsResponse, error) {
+	chrt, err := loader.Load(dir)
+
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "failed to load chart: %v", err)
+	}
+
+	if len(chrt.Schema) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "chart has no values.schema.json")
+	}
+
+	var schema map[string]interface{}
+	err = json.Unmarshal(chrt.Schema, &schema)
+
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "values.schema.json: %v", err)
+	}
+
+	uiSchema := make(map[string]interface{})
+	manifests, issues, err := readManifests(dir, "")
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(issues) > 0 {
+		return nil, status.Error(codes.InvalidArgument, formatIssues(issues))
+	}
+
+	if manifest, ok := manifests["uischema"]; ok {
+		uiSchema = manifest.Data
+	}
+
+	resp := ManifestsResponse{Type: MANIFEST_TYPE_HELM}
+
+	if resp.Data, err = structpb.NewStruct(jsonCompatible(chrt.Values)); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "values.yaml: %v", err)
+	}
+
+	if resp.Schema, err = structpb.NewStruct(schema); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "values.schema.json: %v", err)
+	}
+
+	if resp.UiSchema, err = structpb.NewStruct(uiSchema); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "uischema: %v", err)
+	}
+
+	return &resp, nil
+}
+
+// RenderChart renders the chart at the requested path with the submitted
+// values. Rendering happens locally, lookups against a cluster return empty
+// results. The chart must render exactly one workload object, a batch/v1 Job
+// unless the request names another kind. Every other object is created next
+// to it.
+func (s RepoService) RenderChart(ctx context.Context, request *RenderRequest) (*RenderResponse, error) {
+	release, err := s.readPath(ctx, request.Path)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer release()
+
+	chartDir := filepath.Join(os.Getenv(REPO_ROOT), request.Path)
+
+	if !isChart(chartDir) {
+		return nil, status.Errorf(codes.NotFound, "no chart found at %s", request.Path)
+	}
+
+	chrt, err := loader.Load(chartDir)
+
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "failed to load chart: %v", err)
+	}
+
+	values := request.Values.AsMap()
+	manifest, err := renderChart(chrt, values, request.ReleaseName, request.Namespace)
+
+	if err != nil {
+		logError("failed to render chart", err)
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	parsed, err := manifestPkg.ParseWorkload([]byte(manifest), request.Workload)
+
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	return renderResponse(manifest, parsed)
+}
+
+// renderChart validates values against the chart schema and renders its
+// templates into a single multi document manifest.
+func renderChart(chrt *chart.Chart, values map[string]interface{}, releaseName string, namespace string) (string, error) {
+	if releaseName == "" {
+		releaseName = chrt.Name()
+	}
+
+	if namespace == "" {
+		namespace = defaultNamespace
+	}
+
+	if err := chartutil.ProcessDependenciesWithMerge(chrt, values); err != nil {
+		return "", err
+	}
+
+	options := chartutil.ReleaseOptions{Name: releaseName, Namespace: namespace, IsInstall: true}
+	renderValues, err := chartutil.ToRenderValues(chrt, values, options, chartutil.DefaultCapabilities)
+
+	if err != nil {
+		return "", err
+	}
+
+	rendered, err := engine.Render(chrt, renderValues)
+
+	if err != nil {
+		return "", err
+	}
+
+	names := make([]string, 0, len(rendered))
+
+	for name, contents := range rendered {
+		if strings.HasSuffix(name, "NOTES.txt") || strings.TrimSpace(contents) == "" {
+			continue
+		}
+
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	var manifest strings.Builder
+
+	for _, name := range names {
+		fmt.Fprintf(&manifest, "---\n# Source: %s\n%s\n", name, rendered[name])
+	}
+
+	return manifest.String(), nil
+}
+
+// jsonCompatible round trips values through JSON so they only hold types
+// structpb accepts.
+func jsonCompatible(values map[string]interface{}) map[string]interface{} {
+	result := make(map[string]interface{})
+	raw, err := json.Marshal(values)
+
+	if err != nil {
+		return result
+	}
+
+	json.Unmarshal(raw, &result)
+	return result
+}
@@ -0,0 +1,290 @@
+package reposerver
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+
+	manifestPkg "github.com/kubefill/kubefill/pkg/manifest"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	structpb "google.golang.org/protobuf/types/known/structpb"
+	batchv1 "k8s.io/api/batch/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/kustomize/api/konfig"
+	"sigs.k8s.io/kustomize/api/krusty"
+	"sigs.k8s.io/kustomize/kyaml/filesys"
+)
+
+const MANIFEST_TYPE_KUSTOMIZE = "kustomize"
+
+// OVERLAYS_DIR holds one directory per overlay next to the schema files.
+const OVERLAYS_DIR = "overlays"
+
+// BASE_OVERLAY names the kustomization at the application root itself.
+const BASE_OVERLAY = "base"
+
+func hasKustomization(dir string) bool {
+	for _, name := range konfig.RecognizedKustomizationFileNames() {
+		if info, err := os.Stat(filepath.Join(dir, name)); err == nil && !info.IsDir() {
+			return true
+		}
+	}
+
+	return false
+}
+
+// listOverlays returns the overlays of a kustomize application, the root
+// kustomization first when there is one.
+func listOverlays(dir string) []string {
+	var overlays []string
+
+	if hasKustomization(dir) {
+		overlays = append(overlays, BASE_OVERLAY)
+	}
+
+	entries, err := os.ReadDir(filepath.Join(dir, OVERLAYS_DIR))
+
+	if err != nil {
+		return overlays
+	}
+
+	var names []string
+
+	for _, entry := range entries {
+		if entry.Name() == BASE_OVERLAY && len(overlays) > 0 {
+			continue
+		}
+
+		if entry.IsDir() && hasKustomization(filepath.Join(dir, OVERLAYS_DIR, entry.Name())) {
+			names = append(names, entry.Name())
+		}
+	}
+
+	sort.Strings(names)
+	return append(overlays, names...)
+}
+
+func isKustomize(dir string) bool {
+	return len(listOverlays(dir)) > 0
+}
+
+// overlayDir resolves an overlay name to its directory. Unknown names are
+// rejected so requests cannot point kustomize outside the application.
+func overlayDir(dir string, overlay string) (string, string, error) {
+	overlays := listOverlays(dir)
+
+	if overlay == "" {
+		overlay = overlays[0]
+	}
+
+	if !contains(overlays, overlay) {
+		return "", "", status.Errorf(codes.NotFound, "unknown overlay %q", overlay)
+	}
+
+	if overlay == BASE_OVERLAY && hasKustomization(dir) {
+		return overlay, dir, nil
+	}
+
+	return overlay, filepath.Join(dir, OVERLAYS_DIR, overlay), nil
+}
+
+// buildOverlay runs kustomize on the overlay directory and returns the
+// resulting multi document manifest.
+func buildOverlay(dir string) (string, error) {
+	kustomizer := krusty.MakeKustomizer(krusty.MakeDefaultOptions())
+	resources, err := kustomizer.Run(filesys.MakeFsOnDisk(), dir)
+
+	if err != nil {
+		return "", err
+	}
+
+	manifest, err := resources.AsYaml()
+
+	if err != nil {
+		return "", err
+	}
+
+	return string(manifest), nil
+}
+
+// buildOverlayJob builds the overlay, splits off its workload and applies
+// patch on top of it.
+func buildOverlayJob(dir string, patch map[string]interface{}, workload string) (string, *manifestPkg.Manifest, error) {
+	manifest, err := buildOverlay(dir)
+
+	if err != nil {
+		return "", nil, err
+	}
+
+	parsed, err := manifestPkg.ParseWorkload([]byte(manifest), workload)
+
+	if err != nil {
+		return "", nil, err
+	}
+
+	if len(patch) == 0 {
+		return manifest, parsed, nil
+	}
+
+	if manifestPkg.IsJob(workload) {
+		patched, err := strategicpatch.StrategicMergeMapPatch(parsed.Job, patch, batchv1.Job{})
+
+		if err != nil {
+			return "", nil, err
+		}
+
+		parsed.Job = map[string]interface{}{"metadata": patched["metadata"], "spec": patched["spec"]}
+		return manifest, parsed, nil
+	}
+
+	// Built in kinds get strategic merge semantics, custom resources fall
+	// back to a JSON merge patch.
+	apiVersion, kind := manifestPkg.SplitWorkload(workload)
+	gvk := schema.FromAPIVersionAndKind(apiVersion, kind)
+
+	if object, err := scheme.Scheme.New(gvk); err == nil {
+		patched, err := strategicpatch.StrategicMergeMapPatch(parsed.Job, patch, object)
+
+		if err != nil {
+			return "", nil, err
+		}
+
+		parsed.Job = patched
+		return manifest, parsed, nil
+	}
+
+	parsed.Job = manifestPkg.MergePatch(parsed.Job, patch)
+	return manifest, parsed, nil
+}
+
+// getKustomizeManifests returns the schema files of the application with the
+// Job built from the selected overlay as form defaults.
+func getKustomizeManifests(dir string, overlay string, workload string) (*ManifestsResponse, error) {
+	overlay, buildDir, err := overlayDir(dir, overlay)
+
+	if err != nil {
+		return nil, err
+	}
+
+	manifests, issues, err := readManifests(dir, "")
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(issues) > 0 {
+		return nil, status.Error(codes.InvalidArgument, formatIssues(issues))
+	}
+
+	_, parsed, err := buildOverlayJob(buildDir, nil, workload)
+
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "failed to build overlay %s: %v", overlay, err)
+	}
+
+	resp := ManifestsResponse{Type: MANIFEST_TYPE_KUSTOMIZE, Overlay: overlay, Overlays: listOverlays(dir), UiSchema: &structpb.Struct{}}
+
+	if resp.Data, err = structpb.NewStruct(jsonCompatible(parsed.Job)); err != nil {
+		return nil, err
+	}
+
+	if resp.Resources, err = resourceStructs(parsed.Resources); err != nil {
+		return nil, err
+	}
+
+	for baseFileName, manifest := range manifests {
+		details, err := structpb.NewStruct(manifest.Data)
+
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "%s: %v", manifest.Name, err)
+		}
+
+		if baseFileName == "schema" {
+			resp.Schema = details
+		}
+
+		if baseFileName == "uischema" {
+			resp.UiSchema = details
+		}
+	}
+
+	return &resp, nil
+}
+
+// BuildOverlay builds the requested overlay of a kustomize application and
+// patches the submitted form values onto its Job.
+func (s RepoService) BuildOverlay(ctx context.Context, request *BuildRequest) (*RenderResponse, error) {
+	release, err := s.readPath(ctx, request.Path)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer release()
+
+	appDir := filepath.Join(os.Getenv(REPO_ROOT), request.Path)
+
+	if !isKustomize(appDir) {
+		return nil, status.Errorf(codes.NotFound, "no kustomization found at %s", request.Path)
+	}
+
+	overlay, buildDir, err := overlayDir(appDir, request.Overlay)
+
+	if err != nil {
+		return nil, err
+	}
+
+	manifest, parsed, err := buildOverlayJob(buildDir, request.Patch.AsMap(), request.Workload)
+
+	if err != nil {
+		logError("failed to build overlay "+overlay, err)
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	return renderResponse(manifest, parsed)
+}
+
+// validateKustomize checks the schema files and that every overlay builds a
+// runnable Job.
+func validateKustomize(dir string, workload string) *ValidateManifestsResponse {
+	manifests, issues, err := readManifests(dir, "")
+
+	if err != nil {
+		return validationResponse([]*ManifestIssue{{File: dir, Severity: SEVERITY_ERROR, Message: err.Error()}})
+	}
+
+	schema, ok := manifests["schema"]
+
+	if ok {
+		issues = append(issues, validateSchema(schema)...)
+	} else if !failedFiles(issues)["schema"] {
+		issues = append(issues, &ManifestIssue{File: "schema", Severity: SEVERITY_ERROR, Message: "file is missing"})
+	}
+
+	if uiSchema, ok := manifests["uischema"]; ok && schema != nil {
+		issues = append(issues, validateUiSchema(uiSchema, schema)...)
+	}
+
+	for _, overlay := range listOverlays(dir) {
+		_, buildDir, _ := overlayDir(dir, overlay)
+		_, parsed, err := buildOverlayJob(buildDir, nil, workload)
+		file := filepath.Join(OVERLAYS_DIR, overlay)
+
+		if overlay == BASE_OVERLAY && buildDir == dir {
+			file = konfig.DefaultKustomizationFileName()
+		}
+
+		if err != nil {
+			issues = append(issues, &ManifestIssue{File: file, Severity: SEVERITY_ERROR, Message: err.Error()})
+			continue
+		}
+
+		issues = append(issues, validateData(&manifestFile{Name: file, Data: parsed.Job}, workload)...)
+	}
+
+	return validationResponse(issues)
+}
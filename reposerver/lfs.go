@@ -0,0 +1,517 @@
+package reposerver
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	gitssh "github.com/go-git/go-git/v5/plumbing/transport/ssh"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/crypto/ssh"
+)
+
+const (
+	LFS_POINTER_VERSION  = "version https://git-lfs.github.com/spec/v1"
+	LFS_POINTER_MAX_SIZE = 1024
+	LFS_MEDIA_TYPE       = "application/vnd.git-lfs+json"
+	LFS_BATCH_SIZE       = 100
+	// LFS_MAX_OBJECT_SIZE bounds the size of a single LFS object.
+	LFS_MAX_OBJECT_SIZE = 1 << 30
+)
+
+// lfsClient bounds LFS requests, which otherwise only end with the sync.
+var lfsClient = &http.Client{
+	Timeout: 10 * time.Minute,
+	Transport: &http.Transport{
+		Proxy:                 http.ProxyFromEnvironment,
+		DialContext:           (&net.Dialer{Timeout: 30 * time.Second}).DialContext,
+		TLSHandshakeTimeout:   30 * time.Second,
+		ResponseHeaderTimeout: time.Minute,
+	},
+}
+
+// resolveLfs replaces the Git LFS pointer files of the worktree with the
+// objects they point to. Objects are kept in .git/lfs/objects like git-lfs
+// does, so only new objects are downloaded on the next sync.
+func resolveLfs(ctx context.Context, r *git.Repository, auth *gitssh.PublicKeys, opts cloneOptions, progress ProgressReporter) error {
+	if !opts.Lfs {
+		return nil
+	}
+
+	w, err := r.Worktree()
+
+	if err != nil {
+		return err
+	}
+
+	repoDir := w.Filesystem.Root()
+	pointers, err := findLfsPointers(repoDir, opts.SparsePaths)
+
+	if err != nil || len(pointers) == 0 {
+		return err
+	}
+
+	progress.Phase(SYNC_PHASE_LFS)
+	var missing []lfsObject
+
+	for _, pointer := range pointers {
+		if pointer.Size > LFS_MAX_OBJECT_SIZE {
+			return fmt.Errorf("LFS object %s of %s is %d bytes, more than the limit of %d", pointer.Oid, pointer.Path, pointer.Size, LFS_MAX_OBJECT_SIZE)
+		}
+
+		if _, err := os.Stat(lfsObjectPath(repoDir, pointer.Oid)); os.IsNotExist(err) && !containsObject(missing, pointer.Oid) {
+			missing = append(missing, lfsObject{Oid: pointer.Oid, Size: pointer.Size})
+		}
+	}
+
+	if len(missing) > 0 {
+		remote, err := r.Remote("origin")
+
+		if err != nil {
+			return err
+		}
+
+		err = downloadLfsObjects(ctx, remote.Config().URLs[0], repoDir, auth, missing)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, pointer := range pointers {
+		err := copyFile(lfsObjectPath(repoDir, pointer.Oid), filepath.Join(repoDir, pointer.Path))
+
+		if err != nil {
+			return err
+		}
+	}
+
+	fmt.Fprintf(progress, "resolved %d LFS objects\n", len(pointers))
+	return nil
+}
+
+// findLfsPointers walks the worktree, or only the sparse paths of it, for
+// Git LFS pointer files.
+func findLfsPointers(repoDir string, sparsePaths []string) ([]lfsPointer, error) {
+	var pointers []lfsPointer
+	roots := []string{repoDir}
+
+	if len(sparsePaths) > 0 {
+		roots = nil
+
+		for _, p := range sparsePaths {
+			roots = append(roots, filepath.Join(repoDir, p))
+		}
+	}
+
+	for _, root := range roots {
+		err := filepath.WalkDir(root, func(filePath string, d fs.DirEntry, err error) error {
+			if err != nil {
+				if os.IsNotExist(err) {
+					return nil
+				}
+
+				return err
+			}
+
+			if d.IsDir() {
+				if d.Name() == ".git" {
+					return filepath.SkipDir
+				}
+
+				return nil
+			}
+
+			info, err := d.Info()
+
+			if err != nil || !info.Mode().IsRegular() || info.Size() > LFS_POINTER_MAX_SIZE {
+				return err
+			}
+
+			contents, err := os.ReadFile(filePath)
+
+			if err != nil {
+				return err
+			}
+
+			pointer, ok := parseLfsPointer(contents)
+
+			if ok {
+				pointer.Path, _ = filepath.Rel(repoDir, filePath)
+				pointers = append(pointers, pointer)
+			}
+
+			return nil
+		})
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return pointers, nil
+}
+
+// copyFile streams an object of the LFS store over its pointer file.
+func copyFile(src string, dst string) error {
+	in, err := os.Open(src)
+
+	if err != nil {
+		return err
+	}
+
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(out, in)
+
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+
+	return err
+}
+
+func parseLfsPointer(contents []byte) (lfsPointer, bool) {
+	var pointer lfsPointer
+
+	if !bytes.HasPrefix(contents, []byte(LFS_POINTER_VERSION)) {
+		return pointer, false
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(contents))
+
+	for scanner.Scan() {
+		key, value, _ := strings.Cut(scanner.Text(), " ")
+
+		switch key {
+		case "oid":
+			pointer.Oid = strings.TrimPrefix(value, "sha256:")
+		case "size":
+			pointer.Size, _ = strconv.ParseInt(value, 10, 64)
+		}
+	}
+
+	return pointer, len(pointer.Oid) == sha256.Size*2
+}
+
+func lfsObjectPath(repoDir string, oid string) string {
+	return filepath.Join(repoDir, ".git", "lfs", "objects", oid[0:2], oid[2:4], oid)
+}
+
+func containsObject(objects []lfsObject, oid string) bool {
+	for _, object := range objects {
+		if object.Oid == oid {
+			return true
+		}
+	}
+
+	return false
+}
+
+// downloadLfsObjects fetches objects through the LFS batch API of the remote.
+// Local remotes are read straight from their LFS object store.
+func downloadLfsObjects(ctx context.Context, remoteUrl string, repoDir string, auth *gitssh.PublicKeys, objects []lfsObject) error {
+	gitUrl, err := ParseGitURL(remoteUrl)
+
+	if err != nil {
+		return err
+	}
+
+	if gitUrl.IsLocal() {
+		for _, object := range objects {
+			err := copyLocalLfsObject(gitUrl.Path, repoDir, object)
+
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	endpoint, err := lfsEndpoint(remoteUrl, gitUrl, auth)
+
+	if err != nil {
+		return err
+	}
+
+	for start := 0; start < len(objects); start += LFS_BATCH_SIZE {
+		end := start + LFS_BATCH_SIZE
+
+		if end > len(objects) {
+			end = len(objects)
+		}
+
+		batch, err := lfsBatch(ctx, endpoint, objects[start:end])
+
+		if err != nil {
+			return err
+		}
+
+		for _, object := range batch {
+			if object.Error != nil {
+				return fmt.Errorf("LFS object %s: %s", object.Oid, object.Error.Message)
+			}
+
+			download, ok := object.Actions["download"]
+
+			if !ok {
+				return fmt.Errorf("LFS object %s has no download action", object.Oid)
+			}
+
+			err = downloadLfsObject(ctx, endpoint, download, repoDir, object)
+
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func lfsBatch(ctx context.Context, endpoint *lfsAction, objects []lfsObject) ([]lfsObject, error) {
+	body, err := json.Marshal(lfsBatchRequest{
+		Operation: "download",
+		Transfers: []string{"basic"},
+		Objects:   objects,
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimSuffix(endpoint.Href, "/")+"/objects/batch", bytes.NewReader(body))
+
+	if err != nil {
+		return nil, err
+	}
+
+	for k, v := range endpoint.Header {
+		req.Header.Set(k, v)
+	}
+
+	req.Header.Set("Accept", LFS_MEDIA_TYPE)
+	req.Header.Set("Content-Type", LFS_MEDIA_TYPE)
+	resp, err := lfsClient.Do(req)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("LFS batch request failed with status %s", resp.Status)
+	}
+
+	var batch lfsBatchResponse
+	err = json.NewDecoder(io.LimitReader(resp.Body, maxFileSize)).Decode(&batch)
+	return batch.Objects, err
+}
+
+// downloadLfsObject downloads an object into the LFS store, checking its
+// hash before it is moved in place. Downloads from the host of the endpoint
+// carry its credentials unless the action brings its own.
+func downloadLfsObject(ctx context.Context, endpoint *lfsAction, action *lfsAction, repoDir string, object lfsObject) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, action.Href, nil)
+
+	if err != nil {
+		return err
+	}
+
+	if auth, ok := endpoint.Header["Authorization"]; ok && sameHost(endpoint.Href, action.Href) {
+		req.Header.Set("Authorization", auth)
+	}
+
+	for k, v := range action.Header {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := lfsClient.Do(req)
+
+	if err != nil {
+		return err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to download LFS object %s: %s", object.Oid, resp.Status)
+	}
+
+	return storeLfsObject(repoDir, object, resp.Body)
+}
+
+func copyLocalLfsObject(remoteDir string, repoDir string, object lfsObject) error {
+	candidates := []string{
+		lfsObjectPath(remoteDir, object.Oid),
+		filepath.Join(remoteDir, "lfs", "objects", object.Oid[0:2], object.Oid[2:4], object.Oid),
+	}
+
+	for _, candidate := range candidates {
+		f, err := os.Open(candidate)
+
+		if os.IsNotExist(err) {
+			continue
+		}
+
+		if err != nil {
+			return err
+		}
+
+		defer f.Close()
+		return storeLfsObject(repoDir, object, f)
+	}
+
+	return fmt.Errorf("LFS object %s not found in %s", object.Oid, remoteDir)
+}
+
+func storeLfsObject(repoDir string, object lfsObject, contents io.Reader) error {
+	objectPath := lfsObjectPath(repoDir, object.Oid)
+	err := os.MkdirAll(filepath.Dir(objectPath), os.ModePerm)
+
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(objectPath), object.Oid)
+
+	if err != nil {
+		return err
+	}
+
+	defer os.Remove(tmp.Name())
+
+	// One byte over the pointer size is enough to tell the object is wrong.
+	hash := sha256.New()
+	size, err := io.Copy(io.MultiWriter(tmp, hash), io.LimitReader(contents, object.Size+1))
+	tmp.Close()
+
+	if err != nil {
+		return err
+	}
+
+	if hex.EncodeToString(hash.Sum(nil)) != object.Oid || size != object.Size {
+		return fmt.Errorf("LFS object %s does not match its pointer", object.Oid)
+	}
+
+	return os.Rename(tmp.Name(), objectPath)
+}
+
+// lfsEndpoint returns the LFS API of a remote. SSH remotes are asked for it
+// with git-lfs-authenticate, HTTP remotes serve it under info/lfs.
+func lfsEndpoint(remoteUrl string, gitUrl GitURL, auth *gitssh.PublicKeys) (*lfsAction, error) {
+	if gitUrl.Scheme == SCHEME_HTTP || gitUrl.Scheme == SCHEME_HTTPS {
+		return httpLfsEndpoint(remoteUrl)
+	}
+
+	if !gitUrl.IsSSH() {
+		return nil, fmt.Errorf("LFS is not supported for %s remotes", gitUrl.Scheme)
+	}
+
+	if auth == nil {
+		return nil, fmt.Errorf("no SSH key to authenticate LFS requests to %s", gitUrl.Host)
+	}
+
+	config, err := auth.ClientConfig()
+
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := ssh.Dial("tcp", net.JoinHostPort(gitUrl.Host, gitUrl.Port), config)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer client.Close()
+
+	session, err := client.NewSession()
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer session.Close()
+
+	output, err := session.Output(fmt.Sprintf("git-lfs-authenticate %s download", gitUrl.RepoPath()))
+
+	if err != nil {
+		return nil, fmt.Errorf("git-lfs-authenticate failed: %w", err)
+	}
+
+	var endpoint lfsAction
+	err = json.Unmarshal(output, &endpoint)
+
+	if err != nil {
+		return nil, err
+	}
+
+	log.Infof("LFS endpoint for %s is %s", remoteUrl, endpoint.Href)
+	return &endpoint, nil
+}
+
+// httpLfsEndpoint returns the LFS API of an HTTP remote. Credentials of the
+// remote URL, which git uses for the clone, are moved into a basic
+// Authorization header.
+func httpLfsEndpoint(remoteUrl string) (*lfsAction, error) {
+	u, err := url.Parse(remoteUrl)
+
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint := lfsAction{Header: map[string]string{}}
+
+	if u.User != nil {
+		password, _ := u.User.Password()
+		credentials := base64.StdEncoding.EncodeToString([]byte(u.User.Username() + ":" + password))
+		endpoint.Header["Authorization"] = "Basic " + credentials
+		u.User = nil
+	}
+
+	u.Path = strings.TrimSuffix(u.Path, "/")
+
+	if !strings.HasSuffix(u.Path, ".git") {
+		u.Path += ".git"
+	}
+
+	u.Path += "/info/lfs"
+	endpoint.Href = u.String()
+	return &endpoint, nil
+}
+
+func sameHost(a string, b string) bool {
+	ua, err := url.Parse(a)
+
+	if err != nil {
+		return false
+	}
+
+	ub, err := url.Parse(b)
+	return err == nil && ua.Scheme == ub.Scheme && ua.Host == ub.Host
+}
@@ -0,0 +1,159 @@
+package reposerver
+
+import (
+	"context"
+	"errors"
+	"path"
+	"strings"
+	"sync"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var errSyncInProgress = errors.New("sync already in progress")
+
+// repoLocks guards the checkouts of the repo root. Reads of a checkout run
+// concurrently, a sync waits for them to finish and holds new reads back, so
+// reads never see a half synced or removed working tree.
+type repoLocks struct {
+	mu    sync.Mutex
+	locks map[string]*repoLock
+}
+
+type repoLock struct {
+	mu      sync.Mutex
+	readers int
+	syncing bool
+	// changed is closed, and replaced, whenever the lock is released.
+	changed chan struct{}
+}
+
+func newRepoLocks() *repoLocks {
+	return &repoLocks{locks: make(map[string]*repoLock)}
+}
+
+func (l *repoLocks) get(key string) *repoLock {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	lock, ok := l.locks[key]
+
+	if !ok {
+		lock = &repoLock{changed: make(chan struct{})}
+		l.locks[key] = lock
+	}
+
+	return lock
+}
+
+// read waits for a running sync of the checkout to finish. The returned func
+// releases the read.
+func (l *repoLocks) read(ctx context.Context, key string) (func(), error) {
+	lock := l.get(key)
+
+	for {
+		lock.mu.Lock()
+
+		if !lock.syncing {
+			lock.readers++
+			lock.mu.Unlock()
+
+			return func() {
+				lock.mu.Lock()
+				defer lock.mu.Unlock()
+				lock.readers--
+
+				if lock.readers == 0 {
+					lock.broadcast()
+				}
+			}, nil
+		}
+
+		changed := lock.changed
+		lock.mu.Unlock()
+
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// sync takes the checkout for a sync once the running reads are done. It
+// fails with errSyncInProgress instead of queueing behind another sync.
+func (l *repoLocks) sync(ctx context.Context, key string) (func(), error) {
+	lock := l.get(key)
+	lock.mu.Lock()
+
+	if lock.syncing {
+		lock.mu.Unlock()
+		return nil, errSyncInProgress
+	}
+
+	lock.syncing = true
+	release := func() {
+		lock.mu.Lock()
+		defer lock.mu.Unlock()
+		lock.syncing = false
+		lock.broadcast()
+	}
+
+	for lock.readers > 0 {
+		changed := lock.changed
+		lock.mu.Unlock()
+
+		select {
+		case <-changed:
+			lock.mu.Lock()
+		case <-ctx.Done():
+			release()
+			return nil, ctx.Err()
+		}
+	}
+
+	lock.mu.Unlock()
+	return release, nil
+}
+
+func (lock *repoLock) broadcast() {
+	close(lock.changed)
+	lock.changed = make(chan struct{})
+}
+
+// checkoutKey returns the checkout a path relative to the repo root is in,
+// the repo ID and branch directories.
+func checkoutKey(repoPath string) string {
+	parts := strings.SplitN(strings.Trim(path.Clean("/"+repoPath), "/"), "/", 3)
+
+	if len(parts) > 2 {
+		parts = parts[:2]
+	}
+
+	return strings.Join(parts, "/")
+}
+
+// readPath holds back syncs of the checkout a path relative to the repo root
+// is in until the returned func is called.
+func (s RepoService) readPath(ctx context.Context, repoPath string) (func(), error) {
+	release, err := s.locks.read(ctx, checkoutKey(repoPath))
+
+	if err != nil {
+		return nil, status.FromContextError(err).Err()
+	}
+
+	return release, nil
+}
+
+// readCheckout holds back syncs of a repo branch until the returned func is
+// called.
+func (s RepoService) readCheckout(ctx context.Context, repoId string, branch string) (func(), error) {
+	relPath, err := checkoutPath(repoId, branch)
+
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	return s.readPath(ctx, relPath)
+}
@@ -0,0 +1,56 @@
+package reposerver
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+const (
+	SYNC_PHASE_STARTED    = "started"
+	SYNC_PHASE_CLONING    = "cloning"
+	SYNC_PHASE_PULLING    = "pulling"
+	SYNC_PHASE_SUBMODULES = "submodules"
+	SYNC_PHASE_LFS        = "lfs"
+	SYNC_PHASE_DONE       = "done"
+)
+
+func (p stdoutProgress) Write(data []byte) (int, error) {
+	return os.Stdout.Write(data)
+}
+
+func (p stdoutProgress) Phase(phase string) {
+	fmt.Println("Sync phase", phase)
+}
+
+// Write forwards go-git progress to the client. go-git rewrites the current
+// line with carriage returns, so every chunk is split and sent line by line.
+func (p streamProgress) Write(data []byte) (int, error) {
+	lines := strings.FieldsFunc(string(data), func(r rune) bool {
+		return r == '\r' || r == '\n'
+	})
+
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+
+		if line == "" {
+			continue
+		}
+
+		err := p.stream.Send(&SyncProgress{Message: line})
+
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	return len(data), nil
+}
+
+func (p streamProgress) Phase(phase string) {
+	err := p.stream.Send(&SyncProgress{Phase: phase})
+
+	if err != nil {
+		logError("failed to send sync phase", err)
+	}
+}
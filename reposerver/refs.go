@@ -0,0 +1,389 @@
+package reposerver
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	gitssh "github.com/go-git/go-git/v5/plumbing/transport/ssh"
+	"github.com/go-git/go-git/v5/storage/memory"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ListRefs lists the branches and tags of a remote and the branch its HEAD
+// points to. It authenticates with the key in the request, or else with the
+// saved key of the repo. It fails with FailedPrecondition when an SSH remote
+// has no key to list it with, and with Unavailable when the listing fails.
+func (s RepoService) ListRefs(ctx context.Context, request *ListRefsRequest) (*ListRefsResponse, error) {
+	gitUrl, err := s.parseRemote(request.RepoUrl)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var auth transport.AuthMethod
+
+	if gitUrl.IsSSH() {
+		auth, err = s.listAuth(request)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	trustHost(gitUrl)
+	remote := git.NewRemote(memory.NewStorage(), &config.RemoteConfig{Name: "origin", URLs: []string{request.RepoUrl}})
+	refs, err := remote.ListContext(ctx, &git.ListOptions{Auth: auth})
+
+	if ctx.Err() != nil {
+		return nil, status.FromContextError(ctx.Err()).Err()
+	}
+
+	if err != nil {
+		return nil, status.Errorf(codes.Unavailable, "failed to list the refs of %s: %v", request.RepoUrl, err)
+	}
+
+	resp := ListRefsResponse{}
+	var head *plumbing.Reference
+
+	for _, ref := range refs {
+		switch {
+		case ref.Name() == plumbing.HEAD:
+			head = ref
+		case ref.Name().IsBranch():
+			resp.Branches = append(resp.Branches, &Ref{Name: ref.Name().Short(), Hash: ref.Hash().String()})
+		case ref.Name().IsTag():
+			resp.Tags = append(resp.Tags, &Ref{Name: ref.Name().Short(), Hash: ref.Hash().String()})
+		}
+	}
+
+	sort.Slice(resp.Branches, func(i, j int) bool { return resp.Branches[i].Name < resp.Branches[j].Name })
+	sort.Slice(resp.Tags, func(i, j int) bool { return resp.Tags[i].Name < resp.Tags[j].Name })
+	resp.DefaultBranch = defaultBranch(head, resp.Branches)
+
+	return &resp, nil
+}
+
+func (s RepoService) listAuth(request *ListRefsRequest) (transport.AuthMethod, error) {
+	if request.SshKey != "" {
+		auth, err := gitssh.NewPublicKeys("git", []byte(request.SshKey), "")
+
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid SSH private key: %v", err)
+		}
+
+		return auth, nil
+	}
+
+	if request.RepoId == "" {
+		return nil, status.Error(codes.FailedPrecondition, "an SSH private key is required to list the refs of an SSH remote")
+	}
+
+	if err := validateRepoId(request.RepoId); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	auth, err := repoAuth(request.RepoId, request.RepoUrl)
+
+	if err != nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "repo %s has no usable SSH key: %v", request.RepoId, err)
+	}
+
+	return authMethod(auth), nil
+}
+
+// defaultBranch is the branch HEAD points to. Servers which do not advertise
+// the HEAD symref only give its commit, then a branch at that commit is
+// taken, main or master first.
+func defaultBranch(head *plumbing.Reference, branches []*Ref) string {
+	if head == nil {
+		return ""
+	}
+
+	if head.Type() == plumbing.SymbolicReference {
+		return head.Target().Short()
+	}
+
+	var match string
+
+	for _, branch := range branches {
+		if branch.Hash != head.Hash().String() {
+			continue
+		}
+
+		if branch.Name == "main" || branch.Name == "master" {
+			return branch.Name
+		}
+
+		if match == "" {
+			match = branch.Name
+		}
+	}
+
+	return match
+}
+
+const (
+	// refDirPrefix names the checkouts of refs other than the tracked branch,
+	// next to the branch checkouts of a repo. Branch names never start with a
+	// dot, so the two never collide, and pruning removes ref checkouts which
+	// were not used for a while.
+	refDirPrefix = ".ref-"
+	// refBranch is the local branch of a ref checkout.
+	refBranch = "kubefill-ref"
+)
+
+var fullHash = regexp.MustCompile(`^[0-9a-f]{40}$`)
+
+// checkoutRef checks out the commit a branch, tag or full commit hash of the
+// remote points to and returns the checkout, relative to the repo root, and
+// the commit. A commit is checked out once and shared by every request for
+// it, with the sparse paths, submodules and LFS options of the branch
+// checkout. A commit failing the signature policy of the request is
+// PermissionDenied.
+func (s RepoService) checkoutRef(ctx context.Context, request *RepoDirRequest) (string, string, error) {
+	if err := validateRepoId(request.RepoId); err != nil {
+		return "", "", status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	if fullHash.MatchString(request.Ref) {
+		if relPath, ok := s.refCheckout(request.RepoId, request.Ref); ok {
+			if err := s.verifyCheckout(relPath, request.Ref, request.SignaturePolicy); err != nil {
+				return "", "", err
+			}
+
+			return relPath, request.Ref, nil
+		}
+	}
+
+	gitUrl, err := s.parseRemote(request.RepoUrl)
+
+	if err != nil {
+		return "", "", err
+	}
+
+	trustHost(gitUrl)
+	auth, err := repoAuth(request.RepoId, request.RepoUrl)
+
+	if err != nil {
+		return "", "", status.Errorf(codes.FailedPrecondition, "repo %s has no usable SSH key: %v", request.RepoId, err)
+	}
+
+	src, err := remoteRef(ctx, request.RepoUrl, authMethod(auth), request.Ref)
+
+	if err != nil {
+		return "", "", err
+	}
+
+	var opts cloneOptions
+	branchDir, err := s.checkoutDir(request.RepoId, request.RepoUrl, request.Branch)
+
+	if err == nil {
+		opts, _ = readCloneOptions(branchDir)
+	}
+
+	repoDir := filepath.Join(s.repoRoot, request.RepoId)
+	err = os.MkdirAll(repoDir, os.ModePerm)
+
+	if err != nil {
+		return "", "", err
+	}
+
+	// Checked out aside and moved in place once complete, so a ref checkout
+	// which exists is always complete.
+	tmpDir, err := os.MkdirTemp(repoDir, refDirPrefix+"tmp-")
+
+	if err != nil {
+		return "", "", err
+	}
+
+	defer os.RemoveAll(tmpDir)
+
+	log.Infof("Checking out %s of repo %s", request.Ref, request.RepoId)
+	r, err := git.PlainInit(tmpDir, false)
+
+	if err != nil {
+		return "", "", err
+	}
+
+	_, err = r.CreateRemote(&config.RemoteConfig{Name: "origin", URLs: []string{request.RepoUrl}})
+
+	if err != nil {
+		return "", "", err
+	}
+
+	localRef := plumbing.NewBranchReferenceName(refBranch)
+	hash, err := fetchRef(ctx, r, authMethod(auth), src, localRef)
+
+	if ctx.Err() != nil {
+		return "", "", status.FromContextError(ctx.Err()).Err()
+	}
+
+	if err != nil {
+		return "", "", status.Errorf(codes.Unavailable, "failed to fetch %s of %s: %v", request.Ref, request.RepoUrl, err)
+	}
+
+	err = r.Storer.SetReference(plumbing.NewSymbolicReference(plumbing.HEAD, localRef))
+
+	if err != nil {
+		return "", "", err
+	}
+
+	if policy := request.SignaturePolicy; policy.GetVerify() {
+		commit, err := r.CommitObject(*hash)
+
+		if err != nil {
+			return "", "", err
+		}
+
+		if err := verifyCommit(commit, policy); err != nil {
+			return "", "", status.Errorf(codes.PermissionDenied, "commit %s rejected by the signature policy: %v", hash, err)
+		}
+	}
+
+	if relPath, ok := s.refCheckout(request.RepoId, hash.String()); ok {
+		return relPath, hash.String(), nil
+	}
+
+	err = checkoutCommit(ctx, r, refBranch, *hash, auth, opts, stdoutProgress{})
+
+	if err != nil {
+		return "", "", err
+	}
+
+	relPath := filepath.Join(request.RepoId, refDirPrefix+hash.String())
+	err = os.Rename(tmpDir, filepath.Join(s.repoRoot, relPath))
+
+	// Lost the race against another request for the commit.
+	if _, ok := s.refCheckout(request.RepoId, hash.String()); err != nil && !ok {
+		return "", "", err
+	}
+
+	return relPath, hash.String(), nil
+}
+
+// verifyCheckout checks the commit of an existing ref checkout against the
+// policy, which may have been turned on since it was checked out.
+func (s RepoService) verifyCheckout(relPath string, hash string, policy *SignaturePolicy) error {
+	if !policy.GetVerify() {
+		return nil
+	}
+
+	r, err := git.PlainOpen(filepath.Join(s.repoRoot, relPath))
+
+	if err != nil {
+		return err
+	}
+
+	commit, err := r.CommitObject(plumbing.NewHash(hash))
+
+	if err != nil {
+		return err
+	}
+
+	if err := verifyCommit(commit, policy); err != nil {
+		return status.Errorf(codes.PermissionDenied, "commit %s rejected by the signature policy: %v", hash, err)
+	}
+
+	return nil
+}
+
+// fetchRef fetches the commit src points to as localRef. Servers which do
+// not serve commits by hash have all their branches and tags fetched instead,
+// the commit has to be reachable from one of them.
+func fetchRef(ctx context.Context, r *git.Repository, auth transport.AuthMethod, src string, localRef plumbing.ReferenceName) (*plumbing.Hash, error) {
+	err := r.FetchContext(ctx, &git.FetchOptions{
+		RemoteName: "origin",
+		Auth:       auth,
+		RefSpecs:   []config.RefSpec{config.RefSpec(fmt.Sprintf("+%s:%s", src, localRef))},
+		Depth:      1,
+		Tags:       git.NoTags,
+		Force:      true,
+	})
+
+	if errors.Is(err, git.ErrExactSHA1NotSupported) {
+		err = r.FetchContext(ctx, &git.FetchOptions{
+			RemoteName: "origin",
+			Auth:       auth,
+			RefSpecs:   []config.RefSpec{"+refs/heads/*:refs/remotes/origin/*", "+refs/tags/*:refs/tags/*"},
+			Force:      true,
+		})
+
+		if err != nil {
+			return nil, err
+		}
+
+		if _, err := r.CommitObject(plumbing.NewHash(src)); err != nil {
+			return nil, fmt.Errorf("commit %s not found: %w", src, err)
+		}
+
+		err = r.Storer.SetReference(plumbing.NewHashReference(localRef, plumbing.NewHash(src)))
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return r.ResolveRevision(plumbing.Revision(localRef))
+}
+
+// refCheckout returns the checkout of a commit when it exists. Its
+// modification time is reset, which keeps it from being pruned while in use.
+func (s RepoService) refCheckout(repoId string, commit string) (string, bool) {
+	relPath := filepath.Join(repoId, refDirPrefix+commit)
+	dir := filepath.Join(s.repoRoot, relPath)
+
+	if headHash(dir) != commit {
+		return "", false
+	}
+
+	now := time.Now()
+	os.Chtimes(dir, now, now)
+	return relPath, true
+}
+
+// remoteRef returns the branch or tag of the remote which ref names, given
+// by its full or short name, branches first, or else a full commit hash.
+func remoteRef(ctx context.Context, repoUrl string, auth transport.AuthMethod, ref string) (string, error) {
+	remote := git.NewRemote(memory.NewStorage(), &config.RemoteConfig{Name: "origin", URLs: []string{repoUrl}})
+	refs, err := remote.ListContext(ctx, &git.ListOptions{Auth: auth})
+
+	if ctx.Err() != nil {
+		return "", status.FromContextError(ctx.Err()).Err()
+	}
+
+	if err != nil {
+		return "", status.Errorf(codes.Unavailable, "failed to list the refs of %s: %v", repoUrl, err)
+	}
+
+	candidates := []plumbing.ReferenceName{
+		plumbing.ReferenceName(ref),
+		plumbing.NewBranchReferenceName(ref),
+		plumbing.NewTagReferenceName(ref),
+	}
+
+	for _, candidate := range candidates {
+		for _, remoteRef := range refs {
+			if remoteRef.Name() == candidate && (candidate.IsBranch() || candidate.IsTag()) {
+				return candidate.String(), nil
+			}
+		}
+	}
+
+	if fullHash.MatchString(ref) {
+		return ref, nil
+	}
+
+	return "", status.Errorf(codes.NotFound, "ref %s not found in %s, use a branch, tag or full commit hash", ref, repoUrl)
+}
@@ -0,0 +1,219 @@
+package reposerver
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// orphanGracePeriod spares directories modified recently, which may belong
+// to a repo created after the caller listed its repos.
+const orphanGracePeriod = 10 * time.Minute
+
+var repoIdPattern = regexp.MustCompile(`^[0-9]+$`)
+
+// RemoveRepo deletes the checkouts and keys of a deleted repo. It fails with
+// Aborted while one of its branches is syncing.
+func (s RepoService) RemoveRepo(ctx context.Context, request *RemoveRepoRequest) (*RemoveRepoResponse, error) {
+	if err := validateRepoId(request.RepoId); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	err := s.removeRepoDir(ctx, request.RepoId, nil)
+
+	if errors.Is(err, errSyncInProgress) {
+		return nil, status.Errorf(codes.Aborted, "repo %s is syncing, retry once the sync finished", request.RepoId)
+	}
+
+	if err != nil {
+		return nil, status.FromContextError(err).Err()
+	}
+
+	err = os.RemoveAll(filepath.Join(s.sshRoot, request.RepoId))
+
+	if err != nil {
+		return nil, err
+	}
+
+	log.Infof("Removed repo %s", request.RepoId)
+	return &RemoveRepoResponse{}, nil
+}
+
+// PruneRepos removes the directories of the repo root which are not one of
+// the kept checkouts: repos deleted while the reposerver was unreachable,
+// branches a repo no longer tracks and checkouts of the former layout.
+// Checkouts which are syncing or were modified recently are left alone.
+func (s RepoService) PruneRepos(ctx context.Context, request *PruneReposRequest) (*PruneReposResponse, error) {
+	keep := make(map[string]map[string]bool)
+
+	for _, checkout := range request.Keep {
+		if err := validateRepoId(checkout.RepoId); err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+
+		if keep[checkout.RepoId] == nil {
+			keep[checkout.RepoId] = make(map[string]bool)
+		}
+
+		// An invalid branch never had a checkout, the repo is still kept.
+		relPath, err := checkoutPath(checkout.RepoId, checkout.Branch)
+
+		if err == nil {
+			keep[checkout.RepoId][filepath.Base(relPath)] = true
+		}
+	}
+
+	entries, err := os.ReadDir(s.repoRoot)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var removed []string
+
+	for _, entry := range entries {
+		if !entry.IsDir() || !isOrphanCandidate(filepath.Join(s.repoRoot, entry.Name())) {
+			continue
+		}
+
+		branches := keep[entry.Name()]
+		var err error
+
+		if branches == nil {
+			err = s.removeRepoDir(ctx, entry.Name(), nil)
+
+			if err == nil {
+				removed = append(removed, entry.Name())
+			}
+		} else {
+			var removedBranches []string
+			err = s.removeRepoDir(ctx, entry.Name(), func(branchDir string) bool {
+				if branches[branchDir] || !isOrphanCandidate(filepath.Join(s.repoRoot, entry.Name(), branchDir)) {
+					return false
+				}
+
+				removedBranches = append(removedBranches, filepath.Join(entry.Name(), branchDir))
+				return true
+			})
+
+			if err == nil {
+				removed = append(removed, removedBranches...)
+			}
+		}
+
+		if errors.Is(err, errSyncInProgress) {
+			continue
+		}
+
+		if err != nil {
+			return nil, status.FromContextError(err).Err()
+		}
+	}
+
+	for _, dir := range removed {
+		log.Infof("Pruned orphaned checkout %s", dir)
+	}
+
+	s.pruneKeys(keep)
+	return &PruneReposResponse{Removed: removed}, nil
+}
+
+// pruneKeys removes the SSH keys of repos which are not kept, left behind
+// when the removal of a deleted repo failed. Only directories named like a
+// repo ID are considered, the SSH root holds known_hosts too.
+func (s RepoService) pruneKeys(keep map[string]map[string]bool) {
+	entries, err := os.ReadDir(s.sshRoot)
+
+	if err != nil {
+		log.Errorf("failed to list SSH keys: %v", err)
+		return
+	}
+
+	for _, entry := range entries {
+		keyDir := filepath.Join(s.sshRoot, entry.Name())
+
+		if !entry.IsDir() || !repoIdPattern.MatchString(entry.Name()) || keep[entry.Name()] != nil || !isOrphanCandidate(keyDir) {
+			continue
+		}
+
+		err = os.RemoveAll(keyDir)
+
+		if err != nil {
+			log.Errorf("failed to prune SSH keys of repo %s: %v", entry.Name(), err)
+			continue
+		}
+
+		log.Infof("Pruned SSH keys of deleted repo %s", entry.Name())
+	}
+}
+
+// removeRepoDir removes the branch checkouts of a repo directory for which
+// remove returns true, and the directory once it is empty. A nil remove
+// removes every checkout. Checkouts are removed under their sync lock, so no
+// read or sync of them is running.
+func (s RepoService) removeRepoDir(ctx context.Context, repoId string, remove func(branchDir string) bool) error {
+	repoDir := filepath.Join(s.repoRoot, repoId)
+	entries, err := os.ReadDir(repoDir)
+
+	if os.IsNotExist(err) {
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	var releases []func()
+
+	defer func() {
+		for _, release := range releases {
+			release()
+		}
+	}()
+
+	var checkouts []string
+
+	for _, entry := range entries {
+		if remove != nil && !remove(entry.Name()) {
+			continue
+		}
+
+		checkout := repoId + "/" + entry.Name()
+		release, err := s.locks.sync(ctx, checkout)
+
+		if err != nil {
+			return err
+		}
+
+		releases = append(releases, release)
+		checkouts = append(checkouts, checkout)
+	}
+
+	for _, checkout := range checkouts {
+		err = os.RemoveAll(filepath.Join(s.repoRoot, checkout))
+
+		if err != nil {
+			return err
+		}
+
+		if s.manifests != nil {
+			s.manifests.forget(checkout)
+		}
+	}
+
+	// Fails, and keeps the directory, while other checkouts are left in it.
+	os.Remove(repoDir)
+	return nil
+}
+
+func isOrphanCandidate(dir string) bool {
+	info, err := os.Stat(dir)
+	return err == nil && time.Since(info.ModTime()) > orphanGracePeriod
+}
@@ -1,28 +1,41 @@
 package reposerver
 
 import (
-	"encoding/json"
+	"errors"
 	"fmt"
-	"io/fs"
 	"os"
 	"path/filepath"
-	"strings"
 
 	log "github.com/sirupsen/logrus"
 	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	structpb "google.golang.org/protobuf/types/known/structpb"
-	"k8s.io/apimachinery/pkg/util/yaml"
 )
 
 const (
 	REPO_ROOT   = "REPO_ROOT"
 	SSH_ROOT    = "SSH_ROOT"
 	PRIVATE_KEY = "private_key"
+	// CLONE_OPTIONS_FILE records the clone options of a checkout in its .git
+	// directory.
+	CLONE_OPTIONS_FILE = "kubefill-clone.json"
+)
+
+var (
+	validYamlExt     = []string{".yaml", ".yml"}
+	validManifestExt = []string{".yaml", ".yml", ".json"}
+	manifestFiles    = []string{"data", "schema", "uischema"}
 )
 
 type RepoService struct {
 	repoRoot string
 	sshRoot  string
+	locks    *repoLocks
+	// manifests is nil when the manifest cache is disabled.
+	manifests *manifestCache
+	// allowLocalRemotes accepts file:// remotes and absolute paths.
+	allowLocalRemotes bool
 	UnimplementedRepoServiceServer
 }
 
@@ -38,9 +51,19 @@ func (s RepoService) Init() {
 	if err != nil {
 		log.Fatalln(err)
 	}
+
+	err = sealStoredKeys(s.sshRoot)
+
+	if err != nil {
+		log.Fatalln(err)
+	}
 }
 
 func (s RepoService) RemoveSshKey(_ context.Context, request *RemoveSshKeyRequest) (*RemoveSshKeyResponse, error) {
+	if err := validateRepoId(request.RepoId); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
 	sshRoot := os.Getenv(SSH_ROOT)
 	sshRootPath := filepath.Join(sshRoot, request.RepoId)
 	err := os.RemoveAll(sshRootPath)
@@ -52,77 +75,109 @@ func (s RepoService) RemoveSshKey(_ context.Context, request *RemoveSshKeyReques
 }
 
 func (s RepoService) SaveSshKey(_ context.Context, request *SaveSshKeyRequest) (*SaveSshKeyResponse, error) {
-	var mode fs.FileMode = 0777
-
-	sshKey := request.SshKey
-	sshRoot := os.Getenv(SSH_ROOT)
-	sshRootPath := filepath.Join(sshRoot, request.RepoId)
-	sshPath := filepath.Join(sshRootPath, PRIVATE_KEY)
+	keyDir, err := s.keyDir(request.RepoId)
 
-	if _, err := os.Stat(sshRootPath); os.IsNotExist(err) {
-		err := os.Mkdir(sshRootPath, mode)
-
-		if err != nil {
-			return nil, err
-		}
+	if err != nil {
+		return nil, err
 	}
 
-	fo, err := os.OpenFile(sshPath, os.O_RDWR|os.O_CREATE|os.O_TRUNC, mode)
+	data, err := sealPrivateKey([]byte(request.SshKey))
 
-	if err != nil {
+	if errors.Is(err, errNoKeysKey) {
+		log.Warnf("%s is not set, storing the key of repo %s unencrypted", SSH_KEYS_KEY, request.RepoId)
+		data = []byte(request.SshKey)
+	} else if err != nil {
 		return nil, err
 	}
 
-	defer func() {
-		if err := fo.Close(); err != nil {
-			log.Errorln(err)
-		}
-	}()
-
-	data := []byte(sshKey)
-	err = fo.Truncate(0)
+	err = writePrivateKey(filepath.Join(keyDir, PRIVATE_KEY), data)
 
 	if err != nil {
 		return nil, err
 	}
 
-	_, err = fo.Seek(0, 0)
+	// A pasted key replaces any rotation in progress.
+	err = os.Remove(filepath.Join(keyDir, PENDING_PRIVATE_KEY))
 
-	if err != nil {
+	if err != nil && !os.IsNotExist(err) {
 		return nil, err
 	}
 
-	_, err = fo.Write(data)
+	return &SaveSshKeyResponse{}, nil
+}
+
+func (s RepoService) Sync(ctx context.Context, syncRequest *SyncRequest) (*SyncResponse, error) {
+	return s.syncRepo(ctx, syncRequest, stdoutProgress{})
+}
+
+func (s RepoService) SyncStream(syncRequest *SyncRequest, stream RepoService_SyncStreamServer) error {
+	progress := streamProgress{stream: stream}
+	progress.Phase(SYNC_PHASE_STARTED)
+	resp, err := s.syncRepo(stream.Context(), syncRequest, progress)
 
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	return &SaveSshKeyResponse{}, nil
+	return stream.Send(&SyncProgress{
+		Phase:  SYNC_PHASE_DONE,
+		Hash:   resp.Hash,
+		Commit: resp.Commit,
+	})
 }
 
-func (s RepoService) Sync(_ context.Context, syncRequest *SyncRequest) (*SyncResponse, error) {
+func (s RepoService) syncRepo(ctx context.Context, syncRequest *SyncRequest, progress ProgressReporter) (*SyncResponse, error) {
 	repo := syncRequest.Repo
 	repoId := syncRequest.RepoId
 	branch := syncRequest.Branch
 
-	components := strings.Split(repo, ":")
-	repoParts := strings.Split(components[len(components)-1], "/")
-	owner := repoParts[len(repoParts)-2]
-	repoName := strings.TrimSuffix(repoParts[len(repoParts)-1], ".git")
+	gitUrl, err := s.parseRemote(repo)
 
-	baseUrl, port := ParseGitURL(repo)
-	CreateKnownHostsFile()
+	if err != nil {
+		return nil, err
+	}
+
+	relPath, err := checkoutPath(repoId, branch)
 
-	if !CheckHostInKnownHosts(baseUrl) {
-		fmt.Println("Host not in known_hosts", baseUrl, port)
-		AddHostToKnownHosts(baseUrl, port)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
 	}
 
-	r, err := doSync(repoId, repo, branch, getFullRepoDir(s.repoRoot, owner, repoName))
+	release, err := s.locks.sync(ctx, relPath)
+
+	if errors.Is(err, errSyncInProgress) {
+		return nil, status.Errorf(codes.Aborted, "sync of repo %s branch %s already in progress", repoId, branch)
+	}
+
+	if err != nil {
+		return nil, status.FromContextError(err).Err()
+	}
+
+	defer release()
+
+	trustHost(gitUrl)
+	repoDir, err := s.checkoutDir(repoId, repo, branch)
+
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	if s.manifests != nil {
+		// Also after failed syncs, which may have cloned again or rolled back.
+		defer func() { s.manifests.setHead(relPath, headHash(repoDir)) }()
+	}
+
+	previousHash := headHash(repoDir)
+	opts := newCloneOptions(syncRequest)
+	r, err := doSync(ctx, repoId, repo, branch, repoDir, opts, progress)
 
 	if err != nil {
 		logError("failed to sync", err)
+
+		if ctx.Err() != nil {
+			return nil, status.FromContextError(ctx.Err()).Err()
+		}
+
 		return nil, err
 	}
 
@@ -140,25 +195,65 @@ func (s RepoService) Sync(_ context.Context, syncRequest *SyncRequest) (*SyncRes
 		return nil, err
 	}
 
+	// Verified on every sync, so turning the policy on also rejects the
+	// commit already checked out.
+	policy := syncRequest.SignaturePolicy
+
+	if policy.GetVerify() {
+		err = verifyCommit(commit, policy)
+
+		if err != nil {
+			log.Errorf("rejecting commit %s of repo %s: %v", ref.Hash(), repoId, err)
+			rollbackErr := rollbackRepo(ctx, r, repoId, repo, branch, repoDir, acceptedHash(r, previousHash, ref.Hash(), policy), opts)
+
+			// A checkout left on the rejected commit must not be served.
+			if rollbackErr != nil {
+				logError("failed to roll back, removing the checkout", rollbackErr)
+
+				if removeErr := os.RemoveAll(repoDir); removeErr != nil {
+					logError("failed to remove the checkout", removeErr)
+				}
+			}
+
+			return nil, status.Errorf(codes.PermissionDenied, "commit %s rejected by the signature policy: %v", ref.Hash(), err)
+		}
+	}
+
 	return &SyncResponse{
-		Hash:   ref.Hash().String(),
-		Commit: commit.Message,
+		Hash:     ref.Hash().String(),
+		Commit:   commit.Message,
+		UpToDate: previousHash == ref.Hash().String(),
 	}, nil
 }
 
-func (s RepoService) GetRepoDir(_ context.Context, repoDirRequest *RepoDirRequest) (*RepoDirResponse, error) {
-	fmt.Println("Getting repo dir for", repoDirRequest.RepoUrl)
+// GetRepoDir returns the checkout of a repo branch relative to the repo root,
+// or of another ref of the repo, which is checked out on the first request.
+func (s RepoService) GetRepoDir(ctx context.Context, repoDirRequest *RepoDirRequest) (*RepoDirResponse, error) {
+	fmt.Println("Getting repo dir for", repoDirRequest.RepoId, repoDirRequest.Branch, repoDirRequest.Ref)
+
+	if repoDirRequest.Ref != "" {
+		relPath, hash, err := s.checkoutRef(ctx, repoDirRequest)
 
-	repo := repoDirRequest.RepoUrl
-	repoDirResp := RepoDirResponse{}
+		if err != nil {
+			return nil, err
+		}
 
-	components := strings.Split(repo, ":")
-	repoParts := strings.Split(components[len(components)-1], "/")
-	owner := repoParts[len(repoParts)-2]
-	repoName := strings.TrimSuffix(repoParts[len(repoParts)-1], ".git")
+		return &RepoDirResponse{Path: relPath, Hash: hash}, nil
+	}
 
-	repoDirResp.Path = fmt.Sprintf("%s-%s", owner, repoName)
-	return &repoDirResp, nil
+	repoDir, err := s.checkoutDir(repoDirRequest.RepoId, repoDirRequest.RepoUrl, repoDirRequest.Branch)
+
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	relPath, err := filepath.Rel(s.repoRoot, repoDir)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &RepoDirResponse{Path: relPath, Hash: headHash(repoDir)}, nil
 }
 
 func (s RepoService) GetPaths(_ context.Context, pathsRequest *PathsRequest) (*PathsResponse, error) {
@@ -171,63 +266,90 @@ func (s RepoService) GetPaths(_ context.Context, pathsRequest *PathsRequest) (*P
 	return &pathsResp, nil
 }
 
-func (s RepoService) GetManifests(_ context.Context, manifestsRequest *ManifestsRequest) (*ManifestsResponse, error) {
+func (s RepoService) GetManifests(ctx context.Context, manifestsRequest *ManifestsRequest) (*ManifestsResponse, error) {
+	release, err := s.readPath(ctx, manifestsRequest.Path)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer release()
+
+	if s.manifests == nil {
+		return getManifests(manifestsRequest)
+	}
+
+	key, cacheable := s.manifests.key(s.repoRoot, manifestsRequest)
+
+	if cacheable {
+		if resp, ok := s.manifests.get(key); ok {
+			return resp, nil
+		}
+	}
+
+	resp, err := getManifests(manifestsRequest)
+
+	if err == nil && cacheable {
+		s.manifests.add(key, resp)
+	}
+
+	return resp, err
+}
+
+func getManifests(manifestsRequest *ManifestsRequest) (*ManifestsResponse, error) {
 	fmt.Println("Getting manifests", manifestsRequest)
-	manifestResp := ManifestsResponse{}
+	manifestResp := ManifestsResponse{Type: MANIFEST_TYPE_STATIC}
 	rootDir := os.Getenv(REPO_ROOT)
 	repoRoot := filepath.Join(rootDir, manifestsRequest.Path)
 
 	fmt.Println("Reading from", repoRoot)
 
-	files, err := os.ReadDir(repoRoot)
-	validYamlExt := []string{".yaml", ".yml"}
-	allowedFiles := map[string]string{
-		"data":     "",
-		"schema":   "",
-		"uischema": "",
-	}
-
-	for _, file := range files {
-		if !file.IsDir() {
-			var fullFileName = file.Name()
-			var baseFileName = strings.TrimSuffix(fullFileName, filepath.Ext(fullFileName))
-			_, ok := allowedFiles[baseFileName]
-
-			if ok {
-				var result map[string]interface{}
-				path := filepath.Join(repoRoot, file.Name())
-				ext := filepath.Ext(path)
-				contents := readFile(path)
-
-				if contains(validYamlExt, ext) {
-					yaml.Unmarshal([]byte(contents), &result)
-				} else {
-					json.Unmarshal([]byte(contents), &result)
-				}
+	if isChart(repoRoot) {
+		return getChartManifests(repoRoot)
+	}
 
-				details, err := structpb.NewStruct(result)
+	if isKustomize(repoRoot) {
+		return getKustomizeManifests(repoRoot, manifestsRequest.Overlay, manifestsRequest.Workload)
+	}
 
-				if err != nil {
-					panic(err)
-				}
+	if isTemplate(repoRoot) {
+		return getTemplateManifests(repoRoot)
+	}
 
-				if baseFileName == "data" {
-					manifestResp.Data = details
-				}
+	manifests, issues, err := readManifests(repoRoot, manifestsRequest.Workload)
 
-				if baseFileName == "schema" {
-					manifestResp.Schema = details
-				}
+	if err != nil {
+		logError("failed to read manifests", err)
+		return nil, err
+	}
 
-				if baseFileName == "uischema" {
-					manifestResp.UiSchema = details
-				}
+	if len(issues) > 0 {
+		return nil, status.Error(codes.InvalidArgument, formatIssues(issues))
+	}
+
+	for baseFileName, manifest := range manifests {
+		details, err := structpb.NewStruct(manifest.Data)
+
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "%s: %v", manifest.Name, err)
+		}
+
+		if baseFileName == "data" {
+			manifestResp.Data = details
+			manifestResp.Resources, err = resourceStructs(manifest.Resources)
+
+			if err != nil {
+				return nil, status.Errorf(codes.InvalidArgument, "%s: %v", manifest.Name, err)
 			}
 		}
-	}
 
-	if err != nil {
-		fmt.Println(err)
+		if baseFileName == "schema" {
+			manifestResp.Schema = details
+		}
+
+		if baseFileName == "uischema" {
+			manifestResp.UiSchema = details
+		}
 	}
 
 	return &manifestResp, nil
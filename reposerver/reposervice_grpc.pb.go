@@ -23,11 +23,23 @@ const _ = grpc.SupportPackageIsVersion7
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type RepoServiceClient interface {
 	Sync(ctx context.Context, in *SyncRequest, opts ...grpc.CallOption) (*SyncResponse, error)
+	SyncStream(ctx context.Context, in *SyncRequest, opts ...grpc.CallOption) (RepoService_SyncStreamClient, error)
 	SaveSshKey(ctx context.Context, in *SaveSshKeyRequest, opts ...grpc.CallOption) (*SaveSshKeyResponse, error)
 	RemoveSshKey(ctx context.Context, in *RemoveSshKeyRequest, opts ...grpc.CallOption) (*RemoveSshKeyResponse, error)
+	RemoveRepo(ctx context.Context, in *RemoveRepoRequest, opts ...grpc.CallOption) (*RemoveRepoResponse, error)
+	ListRefs(ctx context.Context, in *ListRefsRequest, opts ...grpc.CallOption) (*ListRefsResponse, error)
+	PruneRepos(ctx context.Context, in *PruneReposRequest, opts ...grpc.CallOption) (*PruneReposResponse, error)
+	GenerateDeployKey(ctx context.Context, in *GenerateDeployKeyRequest, opts ...grpc.CallOption) (*GenerateDeployKeyResponse, error)
+	ConfirmDeployKey(ctx context.Context, in *ConfirmDeployKeyRequest, opts ...grpc.CallOption) (*ConfirmDeployKeyResponse, error)
 	GetManifests(ctx context.Context, in *ManifestsRequest, opts ...grpc.CallOption) (*ManifestsResponse, error)
+	ValidateManifests(ctx context.Context, in *ManifestsRequest, opts ...grpc.CallOption) (*ValidateManifestsResponse, error)
+	RenderChart(ctx context.Context, in *RenderRequest, opts ...grpc.CallOption) (*RenderResponse, error)
+	BuildOverlay(ctx context.Context, in *BuildRequest, opts ...grpc.CallOption) (*RenderResponse, error)
 	GetRepoDir(ctx context.Context, in *RepoDirRequest, opts ...grpc.CallOption) (*RepoDirResponse, error)
 	GetPaths(ctx context.Context, in *PathsRequest, opts ...grpc.CallOption) (*PathsResponse, error)
+	ListTree(ctx context.Context, in *TreeRequest, opts ...grpc.CallOption) (*TreeResponse, error)
+	ReadFile(ctx context.Context, in *FileRequest, opts ...grpc.CallOption) (*FileResponse, error)
+	DiscoverApplications(ctx context.Context, in *DiscoverRequest, opts ...grpc.CallOption) (*DiscoverResponse, error)
 }
 
 type repoServiceClient struct {
@@ -47,6 +59,38 @@ func (c *repoServiceClient) Sync(ctx context.Context, in *SyncRequest, opts ...g
 	return out, nil
 }
 
+func (c *repoServiceClient) SyncStream(ctx context.Context, in *SyncRequest, opts ...grpc.CallOption) (RepoService_SyncStreamClient, error) {
+	stream, err := c.cc.NewStream(ctx, &RepoService_ServiceDesc.Streams[0], "/reposerver.RepoService/SyncStream", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &repoServiceSyncStreamClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type RepoService_SyncStreamClient interface {
+	Recv() (*SyncProgress, error)
+	grpc.ClientStream
+}
+
+type repoServiceSyncStreamClient struct {
+	grpc.ClientStream
+}
+
+func (x *repoServiceSyncStreamClient) Recv() (*SyncProgress, error) {
+	m := new(SyncProgress)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 func (c *repoServiceClient) SaveSshKey(ctx context.Context, in *SaveSshKeyRequest, opts ...grpc.CallOption) (*SaveSshKeyResponse, error) {
 	out := new(SaveSshKeyResponse)
 	err := c.cc.Invoke(ctx, "/reposerver.RepoService/SaveSshKey", in, out, opts...)
@@ -65,6 +109,51 @@ func (c *repoServiceClient) RemoveSshKey(ctx context.Context, in *RemoveSshKeyRe
 	return out, nil
 }
 
+func (c *repoServiceClient) RemoveRepo(ctx context.Context, in *RemoveRepoRequest, opts ...grpc.CallOption) (*RemoveRepoResponse, error) {
+	out := new(RemoveRepoResponse)
+	err := c.cc.Invoke(ctx, "/reposerver.RepoService/RemoveRepo", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *repoServiceClient) ListRefs(ctx context.Context, in *ListRefsRequest, opts ...grpc.CallOption) (*ListRefsResponse, error) {
+	out := new(ListRefsResponse)
+	err := c.cc.Invoke(ctx, "/reposerver.RepoService/ListRefs", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *repoServiceClient) PruneRepos(ctx context.Context, in *PruneReposRequest, opts ...grpc.CallOption) (*PruneReposResponse, error) {
+	out := new(PruneReposResponse)
+	err := c.cc.Invoke(ctx, "/reposerver.RepoService/PruneRepos", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *repoServiceClient) GenerateDeployKey(ctx context.Context, in *GenerateDeployKeyRequest, opts ...grpc.CallOption) (*GenerateDeployKeyResponse, error) {
+	out := new(GenerateDeployKeyResponse)
+	err := c.cc.Invoke(ctx, "/reposerver.RepoService/GenerateDeployKey", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *repoServiceClient) ConfirmDeployKey(ctx context.Context, in *ConfirmDeployKeyRequest, opts ...grpc.CallOption) (*ConfirmDeployKeyResponse, error) {
+	out := new(ConfirmDeployKeyResponse)
+	err := c.cc.Invoke(ctx, "/reposerver.RepoService/ConfirmDeployKey", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *repoServiceClient) GetManifests(ctx context.Context, in *ManifestsRequest, opts ...grpc.CallOption) (*ManifestsResponse, error) {
 	out := new(ManifestsResponse)
 	err := c.cc.Invoke(ctx, "/reposerver.RepoService/GetManifests", in, out, opts...)
@@ -74,6 +163,33 @@ func (c *repoServiceClient) GetManifests(ctx context.Context, in *ManifestsReque
 	return out, nil
 }
 
+func (c *repoServiceClient) ValidateManifests(ctx context.Context, in *ManifestsRequest, opts ...grpc.CallOption) (*ValidateManifestsResponse, error) {
+	out := new(ValidateManifestsResponse)
+	err := c.cc.Invoke(ctx, "/reposerver.RepoService/ValidateManifests", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *repoServiceClient) RenderChart(ctx context.Context, in *RenderRequest, opts ...grpc.CallOption) (*RenderResponse, error) {
+	out := new(RenderResponse)
+	err := c.cc.Invoke(ctx, "/reposerver.RepoService/RenderChart", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *repoServiceClient) BuildOverlay(ctx context.Context, in *BuildRequest, opts ...grpc.CallOption) (*RenderResponse, error) {
+	out := new(RenderResponse)
+	err := c.cc.Invoke(ctx, "/reposerver.RepoService/BuildOverlay", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *repoServiceClient) GetRepoDir(ctx context.Context, in *RepoDirRequest, opts ...grpc.CallOption) (*RepoDirResponse, error) {
 	out := new(RepoDirResponse)
 	err := c.cc.Invoke(ctx, "/reposerver.RepoService/GetRepoDir", in, out, opts...)
@@ -92,16 +208,55 @@ func (c *repoServiceClient) GetPaths(ctx context.Context, in *PathsRequest, opts
 	return out, nil
 }
 
+func (c *repoServiceClient) ListTree(ctx context.Context, in *TreeRequest, opts ...grpc.CallOption) (*TreeResponse, error) {
+	out := new(TreeResponse)
+	err := c.cc.Invoke(ctx, "/reposerver.RepoService/ListTree", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *repoServiceClient) ReadFile(ctx context.Context, in *FileRequest, opts ...grpc.CallOption) (*FileResponse, error) {
+	out := new(FileResponse)
+	err := c.cc.Invoke(ctx, "/reposerver.RepoService/ReadFile", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *repoServiceClient) DiscoverApplications(ctx context.Context, in *DiscoverRequest, opts ...grpc.CallOption) (*DiscoverResponse, error) {
+	out := new(DiscoverResponse)
+	err := c.cc.Invoke(ctx, "/reposerver.RepoService/DiscoverApplications", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // RepoServiceServer is the server API for RepoService service.
 // All implementations must embed UnimplementedRepoServiceServer
 // for forward compatibility
 type RepoServiceServer interface {
 	Sync(context.Context, *SyncRequest) (*SyncResponse, error)
+	SyncStream(*SyncRequest, RepoService_SyncStreamServer) error
 	SaveSshKey(context.Context, *SaveSshKeyRequest) (*SaveSshKeyResponse, error)
 	RemoveSshKey(context.Context, *RemoveSshKeyRequest) (*RemoveSshKeyResponse, error)
+	RemoveRepo(context.Context, *RemoveRepoRequest) (*RemoveRepoResponse, error)
+	ListRefs(context.Context, *ListRefsRequest) (*ListRefsResponse, error)
+	PruneRepos(context.Context, *PruneReposRequest) (*PruneReposResponse, error)
+	GenerateDeployKey(context.Context, *GenerateDeployKeyRequest) (*GenerateDeployKeyResponse, error)
+	ConfirmDeployKey(context.Context, *ConfirmDeployKeyRequest) (*ConfirmDeployKeyResponse, error)
 	GetManifests(context.Context, *ManifestsRequest) (*ManifestsResponse, error)
+	ValidateManifests(context.Context, *ManifestsRequest) (*ValidateManifestsResponse, error)
+	RenderChart(context.Context, *RenderRequest) (*RenderResponse, error)
+	BuildOverlay(context.Context, *BuildRequest) (*RenderResponse, error)
 	GetRepoDir(context.Context, *RepoDirRequest) (*RepoDirResponse, error)
 	GetPaths(context.Context, *PathsRequest) (*PathsResponse, error)
+	ListTree(context.Context, *TreeRequest) (*TreeResponse, error)
+	ReadFile(context.Context, *FileRequest) (*FileResponse, error)
+	DiscoverApplications(context.Context, *DiscoverRequest) (*DiscoverResponse, error)
 	mustEmbedUnimplementedRepoServiceServer()
 }
 
@@ -112,21 +267,57 @@ type UnimplementedRepoServiceServer struct {
 func (UnimplementedRepoServiceServer) Sync(context.Context, *SyncRequest) (*SyncResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Sync not implemented")
 }
+func (UnimplementedRepoServiceServer) SyncStream(*SyncRequest, RepoService_SyncStreamServer) error {
+	return status.Errorf(codes.Unimplemented, "method SyncStream not implemented")
+}
 func (UnimplementedRepoServiceServer) SaveSshKey(context.Context, *SaveSshKeyRequest) (*SaveSshKeyResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method SaveSshKey not implemented")
 }
 func (UnimplementedRepoServiceServer) RemoveSshKey(context.Context, *RemoveSshKeyRequest) (*RemoveSshKeyResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method RemoveSshKey not implemented")
 }
+func (UnimplementedRepoServiceServer) RemoveRepo(context.Context, *RemoveRepoRequest) (*RemoveRepoResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveRepo not implemented")
+}
+func (UnimplementedRepoServiceServer) ListRefs(context.Context, *ListRefsRequest) (*ListRefsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListRefs not implemented")
+}
+func (UnimplementedRepoServiceServer) PruneRepos(context.Context, *PruneReposRequest) (*PruneReposResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PruneRepos not implemented")
+}
+func (UnimplementedRepoServiceServer) GenerateDeployKey(context.Context, *GenerateDeployKeyRequest) (*GenerateDeployKeyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GenerateDeployKey not implemented")
+}
+func (UnimplementedRepoServiceServer) ConfirmDeployKey(context.Context, *ConfirmDeployKeyRequest) (*ConfirmDeployKeyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ConfirmDeployKey not implemented")
+}
 func (UnimplementedRepoServiceServer) GetManifests(context.Context, *ManifestsRequest) (*ManifestsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetManifests not implemented")
 }
+func (UnimplementedRepoServiceServer) ValidateManifests(context.Context, *ManifestsRequest) (*ValidateManifestsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ValidateManifests not implemented")
+}
+func (UnimplementedRepoServiceServer) RenderChart(context.Context, *RenderRequest) (*RenderResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RenderChart not implemented")
+}
+func (UnimplementedRepoServiceServer) BuildOverlay(context.Context, *BuildRequest) (*RenderResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BuildOverlay not implemented")
+}
 func (UnimplementedRepoServiceServer) GetRepoDir(context.Context, *RepoDirRequest) (*RepoDirResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetRepoDir not implemented")
 }
 func (UnimplementedRepoServiceServer) GetPaths(context.Context, *PathsRequest) (*PathsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetPaths not implemented")
 }
+func (UnimplementedRepoServiceServer) ListTree(context.Context, *TreeRequest) (*TreeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListTree not implemented")
+}
+func (UnimplementedRepoServiceServer) ReadFile(context.Context, *FileRequest) (*FileResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReadFile not implemented")
+}
+func (UnimplementedRepoServiceServer) DiscoverApplications(context.Context, *DiscoverRequest) (*DiscoverResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DiscoverApplications not implemented")
+}
 func (UnimplementedRepoServiceServer) mustEmbedUnimplementedRepoServiceServer() {}
 
 // UnsafeRepoServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -158,6 +349,27 @@ func _RepoService_Sync_Handler(srv interface{}, ctx context.Context, dec func(in
 	return interceptor(ctx, in, info, handler)
 }
 
+func _RepoService_SyncStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SyncRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(RepoServiceServer).SyncStream(m, &repoServiceSyncStreamServer{stream})
+}
+
+type RepoService_SyncStreamServer interface {
+	Send(*SyncProgress) error
+	grpc.ServerStream
+}
+
+type repoServiceSyncStreamServer struct {
+	grpc.ServerStream
+}
+
+func (x *repoServiceSyncStreamServer) Send(m *SyncProgress) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 func _RepoService_SaveSshKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(SaveSshKeyRequest)
 	if err := dec(in); err != nil {
@@ -194,6 +406,96 @@ func _RepoService_RemoveSshKey_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _RepoService_RemoveRepo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveRepoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RepoServiceServer).RemoveRepo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/reposerver.RepoService/RemoveRepo",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RepoServiceServer).RemoveRepo(ctx, req.(*RemoveRepoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RepoService_ListRefs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListRefsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RepoServiceServer).ListRefs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/reposerver.RepoService/ListRefs",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RepoServiceServer).ListRefs(ctx, req.(*ListRefsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RepoService_PruneRepos_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PruneReposRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RepoServiceServer).PruneRepos(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/reposerver.RepoService/PruneRepos",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RepoServiceServer).PruneRepos(ctx, req.(*PruneReposRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RepoService_GenerateDeployKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GenerateDeployKeyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RepoServiceServer).GenerateDeployKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/reposerver.RepoService/GenerateDeployKey",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RepoServiceServer).GenerateDeployKey(ctx, req.(*GenerateDeployKeyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RepoService_ConfirmDeployKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ConfirmDeployKeyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RepoServiceServer).ConfirmDeployKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/reposerver.RepoService/ConfirmDeployKey",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RepoServiceServer).ConfirmDeployKey(ctx, req.(*ConfirmDeployKeyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _RepoService_GetManifests_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(ManifestsRequest)
 	if err := dec(in); err != nil {
@@ -212,6 +514,60 @@ func _RepoService_GetManifests_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _RepoService_ValidateManifests_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ManifestsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RepoServiceServer).ValidateManifests(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/reposerver.RepoService/ValidateManifests",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RepoServiceServer).ValidateManifests(ctx, req.(*ManifestsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RepoService_RenderChart_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RenderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RepoServiceServer).RenderChart(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/reposerver.RepoService/RenderChart",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RepoServiceServer).RenderChart(ctx, req.(*RenderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RepoService_BuildOverlay_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BuildRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RepoServiceServer).BuildOverlay(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/reposerver.RepoService/BuildOverlay",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RepoServiceServer).BuildOverlay(ctx, req.(*BuildRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _RepoService_GetRepoDir_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(RepoDirRequest)
 	if err := dec(in); err != nil {
@@ -248,6 +604,60 @@ func _RepoService_GetPaths_Handler(srv interface{}, ctx context.Context, dec fun
 	return interceptor(ctx, in, info, handler)
 }
 
+func _RepoService_ListTree_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TreeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RepoServiceServer).ListTree(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/reposerver.RepoService/ListTree",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RepoServiceServer).ListTree(ctx, req.(*TreeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RepoService_ReadFile_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FileRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RepoServiceServer).ReadFile(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/reposerver.RepoService/ReadFile",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RepoServiceServer).ReadFile(ctx, req.(*FileRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RepoService_DiscoverApplications_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DiscoverRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RepoServiceServer).DiscoverApplications(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/reposerver.RepoService/DiscoverApplications",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RepoServiceServer).DiscoverApplications(ctx, req.(*DiscoverRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // RepoService_ServiceDesc is the grpc.ServiceDesc for RepoService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -267,10 +677,42 @@ var RepoService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "RemoveSshKey",
 			Handler:    _RepoService_RemoveSshKey_Handler,
 		},
+		{
+			MethodName: "RemoveRepo",
+			Handler:    _RepoService_RemoveRepo_Handler,
+		},
+		{
+			MethodName: "ListRefs",
+			Handler:    _RepoService_ListRefs_Handler,
+		},
+		{
+			MethodName: "PruneRepos",
+			Handler:    _RepoService_PruneRepos_Handler,
+		},
+		{
+			MethodName: "GenerateDeployKey",
+			Handler:    _RepoService_GenerateDeployKey_Handler,
+		},
+		{
+			MethodName: "ConfirmDeployKey",
+			Handler:    _RepoService_ConfirmDeployKey_Handler,
+		},
 		{
 			MethodName: "GetManifests",
 			Handler:    _RepoService_GetManifests_Handler,
 		},
+		{
+			MethodName: "ValidateManifests",
+			Handler:    _RepoService_ValidateManifests_Handler,
+		},
+		{
+			MethodName: "RenderChart",
+			Handler:    _RepoService_RenderChart_Handler,
+		},
+		{
+			MethodName: "BuildOverlay",
+			Handler:    _RepoService_BuildOverlay_Handler,
+		},
 		{
 			MethodName: "GetRepoDir",
 			Handler:    _RepoService_GetRepoDir_Handler,
@@ -279,7 +721,25 @@ var RepoService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetPaths",
 			Handler:    _RepoService_GetPaths_Handler,
 		},
+		{
+			MethodName: "ListTree",
+			Handler:    _RepoService_ListTree_Handler,
+		},
+		{
+			MethodName: "ReadFile",
+			Handler:    _RepoService_ReadFile_Handler,
+		},
+		{
+			MethodName: "DiscoverApplications",
+			Handler:    _RepoService_DiscoverApplications_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "SyncStream",
+			Handler:       _RepoService_SyncStream_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "reposervice.proto",
 }
@@ -5,6 +5,7 @@ import (
 	"net"
 	"os"
 
+	"github.com/kubefill/kubefill/pkg/grpcauth"
 	"google.golang.org/grpc"
 )
 
@@ -21,12 +22,25 @@ func (s *Server) Run() {
 	}
 
 	service := RepoService{
-		repoRoot: os.Getenv(REPO_ROOT),
-		sshRoot:  os.Getenv(SSH_ROOT),
+		repoRoot:          os.Getenv(REPO_ROOT),
+		sshRoot:           os.Getenv(SSH_ROOT),
+		locks:             newRepoLocks(),
+		manifests:         newManifestCache(s.ManifestCacheSize),
+		allowLocalRemotes: s.AllowLocalRemotes,
 	}
-	grpcServer := grpc.NewServer()
+	opts, err := grpcauth.ServerOptions(s.Auth)
+
+	if err != nil {
+		log.Fatalf("Failed to load the gRPC credentials: %v", err)
+	}
+
+	grpcServer := grpc.NewServer(opts...)
 
 	service.Init()
+
+	if service.manifests != nil {
+		go service.manifests.logStats()
+	}
 	RegisterRepoServiceServer(grpcServer, &service)
 
 	if err := grpcServer.Serve(lis); err != nil {
@@ -0,0 +1,161 @@
+package reposerver
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"strings"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/kubefill/kubefill/pkg/signature"
+	"golang.org/x/crypto/ssh"
+)
+
+const (
+	PGP_SIGNATURE_HEADER = "-----BEGIN PGP SIGNATURE-----"
+	SSH_SIGNATURE_HEADER = "-----BEGIN SSH SIGNATURE-----"
+	SSH_SIGNATURE_MAGIC  = "SSHSIG"
+	// Git signs commits in the git namespace, see ssh-keygen -Y sign.
+	SSH_SIGNATURE_NAMESPACE = "git"
+)
+
+var errUnsigned = errors.New("commit is not signed")
+
+// sshSignature is the blob of an armored SSH signature, see PROTOCOL.sshsig
+// in OpenSSH.
+type sshSignature struct {
+	MagicPreamble [6]byte
+	Version       uint32
+	PublicKey     string
+	Namespace     string
+	Reserved      string
+	HashAlgorithm string
+	Signature     string
+}
+
+// signedSSHData is what the signature of an SSH signature blob covers.
+type signedSSHData struct {
+	MagicPreamble [6]byte
+	Namespace     string
+	Reserved      string
+	HashAlgorithm string
+	Hash          string
+}
+
+// verifyCommit checks that the commit is signed by one of the trusted keys of
+// the policy.
+func verifyCommit(commit *object.Commit, policy *SignaturePolicy) error {
+	if commit.PGPSignature == "" {
+		return errUnsigned
+	}
+
+	pgpKeys, sshKeys, err := signature.TrustedKeys(policy.TrustedKeys)
+
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case strings.HasPrefix(commit.PGPSignature, PGP_SIGNATURE_HEADER):
+		for _, key := range pgpKeys {
+			if _, err := commit.Verify(key); err == nil {
+				return nil
+			}
+		}
+
+		return errors.New("GPG signature is not from a trusted key")
+	case strings.HasPrefix(commit.PGPSignature, SSH_SIGNATURE_HEADER):
+		return verifySSHSignature(commit, sshKeys)
+	}
+
+	return errors.New("unsupported signature format")
+}
+
+func verifySSHSignature(commit *object.Commit, trusted []ssh.PublicKey) error {
+	block, _ := pem.Decode([]byte(commit.PGPSignature))
+
+	if block == nil {
+		return errors.New("invalid SSH signature")
+	}
+
+	var signature sshSignature
+	err := ssh.Unmarshal(block.Bytes, &signature)
+
+	if err != nil {
+		return fmt.Errorf("invalid SSH signature: %w", err)
+	}
+
+	if string(signature.MagicPreamble[:]) != SSH_SIGNATURE_MAGIC || signature.Namespace != SSH_SIGNATURE_NAMESPACE {
+		return errors.New("invalid SSH signature")
+	}
+
+	publicKey, err := ssh.ParsePublicKey([]byte(signature.PublicKey))
+
+	if err != nil {
+		return err
+	}
+
+	if !containsKey(trusted, publicKey) {
+		return fmt.Errorf("SSH signature is not from a trusted key (%s)", ssh.FingerprintSHA256(publicKey))
+	}
+
+	var h hash.Hash
+
+	switch signature.HashAlgorithm {
+	case "sha256":
+		h = sha256.New()
+	case "sha512":
+		h = sha512.New()
+	default:
+		return fmt.Errorf("unsupported SSH signature hash %s", signature.HashAlgorithm)
+	}
+
+	encoded := &plumbing.MemoryObject{}
+
+	if err := commit.EncodeWithoutSignature(encoded); err != nil {
+		return err
+	}
+
+	reader, err := encoded.Reader()
+
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(h, reader); err != nil {
+		return err
+	}
+
+	signed := signedSSHData{
+		Namespace:     signature.Namespace,
+		Reserved:      signature.Reserved,
+		HashAlgorithm: signature.HashAlgorithm,
+		Hash:          string(h.Sum(nil)),
+	}
+	copy(signed.MagicPreamble[:], SSH_SIGNATURE_MAGIC)
+
+	var sig ssh.Signature
+	err = ssh.Unmarshal([]byte(signature.Signature), &sig)
+
+	if err != nil {
+		return fmt.Errorf("invalid SSH signature: %w", err)
+	}
+
+	return publicKey.Verify(ssh.Marshal(signed), &sig)
+}
+
+func containsKey(keys []ssh.PublicKey, key ssh.PublicKey) bool {
+	for _, k := range keys {
+		if bytes.Equal(k.Marshal(), key.Marshal()) {
+			return true
+		}
+	}
+
+	return false
+}
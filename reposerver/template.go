@@ -0,0 +1,116 @@
+package reposerver
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+
+	"github.com/kubefill/kubefill/pkg/tmpl"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	structpb "google.golang.org/protobuf/types/known/structpb"
+)
+
+const MANIFEST_TYPE_TEMPLATE = "template"
+
+func isTemplate(dir string) bool {
+	info, err := os.Stat(filepath.Join(dir, tmpl.TEMPLATE_FILE))
+	return err == nil && !info.IsDir()
+}
+
+// getTemplateManifests returns the schema files and the raw job template.
+// An optional data file provides the form defaults. The template is rendered
+// by the server, which holds the secrets.
+func getTemplateManifests(dir string) (*ManifestsResponse, error) {
+	contents, err := os.ReadFile(filepath.Join(dir, tmpl.TEMPLATE_FILE))
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tmpl.Parse(string(contents)); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	manifests, issues, err := readManifests(dir, "")
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(issues) > 0 {
+		return nil, status.Error(codes.InvalidArgument, formatIssues(issues))
+	}
+
+	resp := ManifestsResponse{
+		Type:     MANIFEST_TYPE_TEMPLATE,
+		Template: string(contents),
+		Data:     &structpb.Struct{},
+		UiSchema: &structpb.Struct{},
+	}
+
+	for baseFileName, manifest := range manifests {
+		details, err := structpb.NewStruct(manifest.Data)
+
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "%s: %v", manifest.Name, err)
+		}
+
+		if baseFileName == "data" {
+			resp.Data = details
+		}
+
+		if baseFileName == "schema" {
+			resp.Schema = details
+		}
+
+		if baseFileName == "uischema" {
+			resp.UiSchema = details
+		}
+	}
+
+	return &resp, nil
+}
+
+// validateTemplate checks the template syntax and the schema files. The
+// template is not executed since its inputs are only known at run time.
+func validateTemplate(dir string) *ValidateManifestsResponse {
+	var issues []*ManifestIssue
+	contents, err := os.ReadFile(filepath.Join(dir, tmpl.TEMPLATE_FILE))
+
+	if err != nil {
+		return validationResponse([]*ManifestIssue{{File: tmpl.TEMPLATE_FILE, Severity: SEVERITY_ERROR, Message: err.Error()}})
+	}
+
+	var renderError *tmpl.RenderError
+
+	if err := tmpl.Parse(string(contents)); errors.As(err, &renderError) {
+		issues = append(issues, &ManifestIssue{
+			File:     tmpl.TEMPLATE_FILE,
+			Line:     int32(renderError.Line),
+			Severity: SEVERITY_ERROR,
+			Message:  renderError.Message,
+		})
+	}
+
+	manifests, parseIssues, err := readManifests(dir, "")
+
+	if err != nil {
+		return validationResponse(append(issues, &ManifestIssue{File: dir, Severity: SEVERITY_ERROR, Message: err.Error()}))
+	}
+
+	issues = append(issues, parseIssues...)
+	schema, ok := manifests["schema"]
+
+	if ok {
+		issues = append(issues, validateSchema(schema)...)
+	} else if !failedFiles(parseIssues)["schema"] {
+		issues = append(issues, &ManifestIssue{File: "schema", Severity: SEVERITY_ERROR, Message: "file is missing"})
+	}
+
+	if uiSchema, ok := manifests["uischema"]; ok && schema != nil {
+		issues = append(issues, validateUiSchema(uiSchema, schema)...)
+	}
+
+	return validationResponse(issues)
+}
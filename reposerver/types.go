@@ -1,5 +1,11 @@
 package reposerver
 
+import (
+	"io"
+
+	"github.com/kubefill/kubefill/pkg/grpcauth"
+)
+
 type ManifestResponses struct {
 	Data      map[string]interface{} `json:"data"`
 	UI_Schema map[string]interface{} `json:"ui_schema"`
@@ -8,8 +14,79 @@ type ManifestResponses struct {
 
 type ServerConfig struct {
 	RootDir string
+	Auth    grpcauth.Config
+	// ManifestCacheSize is the number of parsed manifests kept, 0 disables
+	// the cache.
+	ManifestCacheSize int
+	// AllowLocalRemotes lets repos clone local paths of the reposerver.
+	AllowLocalRemotes bool
 }
 
 type Server struct {
 	ServerConfig
 }
+
+// ProgressReporter receives go-git sideband progress output and the phase
+// changes of a sync.
+type ProgressReporter interface {
+	io.Writer
+	Phase(phase string)
+}
+
+// manifestFile is a parsed data, schema or uischema file. Resources holds the
+// documents of a data file next to the Job.
+type manifestFile struct {
+	Name      string
+	Contents  []byte
+	Data      map[string]interface{}
+	Resources []map[string]interface{}
+}
+
+// cloneOptions controls how a repo is cloned and kept up to date. They are
+// recorded in the checkout so a change of depth or submodules re-clones it.
+type cloneOptions struct {
+	Depth       int      `json:"depth"`
+	Submodules  bool     `json:"submodules"`
+	SparsePaths []string `json:"sparse_paths"`
+	Lfs         bool     `json:"lfs"`
+}
+
+// lfsPointer is a Git LFS pointer file found in the worktree.
+type lfsPointer struct {
+	Path string
+	Oid  string
+	Size int64
+}
+
+type lfsObject struct {
+	Oid     string                `json:"oid"`
+	Size    int64                 `json:"size"`
+	Actions map[string]*lfsAction `json:"actions,omitempty"`
+	Error   *lfsError             `json:"error,omitempty"`
+}
+
+type lfsAction struct {
+	Href   string            `json:"href"`
+	Header map[string]string `json:"header,omitempty"`
+}
+
+type lfsError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+type lfsBatchRequest struct {
+	Operation string      `json:"operation"`
+	Transfers []string    `json:"transfers"`
+	Objects   []lfsObject `json:"objects"`
+}
+
+type lfsBatchResponse struct {
+	Objects []lfsObject `json:"objects"`
+}
+
+type stdoutProgress struct{}
+
+type streamProgress struct {
+	stream RepoService_SyncStreamServer
+}
@@ -2,36 +2,30 @@ package reposerver
 
 import (
 	"bufio"
+	"context"
+	"encoding/json"
 	"fmt"
 	"os"
 	"os/exec"
-	"regexp"
+	"path"
+	"path/filepath"
 	"runtime"
+	"sort"
 	"strings"
 
 	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
 	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/transport"
 	"github.com/go-git/go-git/v5/plumbing/transport/ssh"
+	"github.com/go-git/go-git/v5/storage/memory"
 
+	manifestPkg "github.com/kubefill/kubefill/pkg/manifest"
 	log "github.com/sirupsen/logrus"
+	structpb "google.golang.org/protobuf/types/known/structpb"
+	"k8s.io/apimachinery/pkg/util/yaml"
 )
 
-func ParseGitURL(url string) (string, string) {
-	re := regexp.MustCompile("^git@(.*):([0-9]+)/")
-	match := re.FindStringSubmatch(url)
-	if match != nil {
-		baseUrl := match[1]
-		port := match[2]
-		return baseUrl, port
-	}
-
-	splitResult := strings.Split(url, "@")
-	baseUrl := strings.Split(splitResult[1], ":")[0]
-	port := "22"
-
-	return baseUrl, port
-}
-
 func logError(message string, err error) {
 	pc, _, _, _ := runtime.Caller(1)
 	functionName := runtime.FuncForPC(pc).Name()
@@ -116,17 +110,14 @@ func initDir(path string) error {
 	return nil
 }
 
-func getFullRepoDir(rootDir string, owner string, repoName string) string {
-	return fmt.Sprintf("%s/%s-%s", rootDir, owner, repoName)
-}
-
-func doSync(repoId string, repoUrl string, repoBranch string, repoDir string) (*git.Repository, error) {
+func doSync(ctx context.Context, repoId string, repoUrl string, repoBranch string, repoDir string, opts cloneOptions, progress ProgressReporter) (*git.Repository, error) {
 	os.Setenv("SSH_KNOWN_HOSTS", "/root/.ssh/known_hosts")
 
 	fmt.Println("Syncing repo", repoId, repoUrl, repoBranch, repoDir)
 
 	if _, err := os.Stat(repoDir); os.IsNotExist(err) {
-		r, err := cloneRepo(repoId, repoUrl, repoBranch, repoDir)
+		progress.Phase(SYNC_PHASE_CLONING)
+		r, err := cloneRepo(ctx, repoId, repoUrl, repoBranch, repoDir, opts, progress)
 
 		if err != nil {
 			logError("failed to clone", err)
@@ -137,7 +128,8 @@ func doSync(repoId string, repoUrl string, repoBranch string, repoDir string) (*
 	}
 
 	if _, err := os.Stat(repoDir); !os.IsNotExist(err) {
-		r, err := pullRepo(repoId, repoUrl, repoBranch, repoDir)
+		progress.Phase(SYNC_PHASE_PULLING)
+		r, err := pullRepo(ctx, repoId, repoUrl, repoBranch, repoDir, opts, progress)
 
 		if err != nil {
 			logError("failed to pull", err)
@@ -151,29 +143,59 @@ func doSync(repoId string, repoUrl string, repoBranch string, repoDir string) (*
 }
 
 func getPublicKey(dirPath string) (*ssh.PublicKeys, error) {
-	var publicKey *ssh.PublicKeys
-	sshPath := dirPath + "/" + PRIVATE_KEY
-	sshKey, err := os.ReadFile(sshPath)
+	publicKey, err := loadPrivateKey(dirPath + "/" + PRIVATE_KEY)
 
 	if err != nil {
-		logError("failed to read public key", err)
+		logError("failed to init public key", err)
 		return nil, err
 	}
 
-	publicKey, err = ssh.NewPublicKeys("git", sshKey, "")
+	return publicKey, err
+}
+
+// trustHost adds the host of an SSH remote to known_hosts.
+func trustHost(gitUrl GitURL) {
+	if !gitUrl.IsSSH() {
+		return
+	}
+
+	CreateKnownHostsFile()
+
+	if !CheckHostInKnownHosts(gitUrl.Host) {
+		fmt.Println("Host not in known_hosts", gitUrl.Host, gitUrl.Port)
+		AddHostToKnownHosts(gitUrl.Host, gitUrl.Port)
+	}
+}
+
+// repoAuth loads the SSH key of a repo. Other remotes are read without
+// authentication.
+func repoAuth(repoId string, repoUrl string) (*ssh.PublicKeys, error) {
+	gitUrl, err := ParseGitURL(repoUrl)
 
 	if err != nil {
-		logError("failed to init public key", err)
 		return nil, err
 	}
 
-	return publicKey, err
+	if !gitUrl.IsSSH() {
+		return nil, nil
+	}
+
+	return getPublicKey(os.Getenv(SSH_ROOT) + "/" + repoId)
 }
 
-func cloneRepo(repoId string, repoUrl string, repoBranch string, repoDir string) (*git.Repository, error) {
-	log.Infof("git clone -b %s --single-branch %s %s", repoBranch, repoUrl, repoDir)
-	dirPath := os.Getenv(SSH_ROOT) + "/" + repoId
-	auth, err := getPublicKey(dirPath)
+// authMethod keeps a missing key a nil interface, which go-git reads as no
+// authentication.
+func authMethod(auth *ssh.PublicKeys) transport.AuthMethod {
+	if auth == nil {
+		return nil
+	}
+
+	return auth
+}
+
+func cloneRepo(ctx context.Context, repoId string, repoUrl string, repoBranch string, repoDir string, opts cloneOptions, progress ProgressReporter) (*git.Repository, error) {
+	log.Infof("git clone -b %s --single-branch --depth %d %s %s", repoBranch, opts.Depth, repoUrl, repoDir)
+	auth, err := repoAuth(repoId, repoUrl)
 	referenceName := fmt.Sprintf("refs/heads/%s", repoBranch)
 
 	if err != nil {
@@ -181,12 +203,16 @@ func cloneRepo(repoId string, repoUrl string, repoBranch string, repoDir string)
 		return nil, err
 	}
 
-	r, err := git.PlainClone(repoDir, false, &git.CloneOptions{
-		Progress:      os.Stdout,
+	// Sparse clones skip the checkout and only check out the manifest paths
+	// in checkoutBranch, which also updates submodules.
+	r, err := git.PlainCloneContext(ctx, repoDir, false, &git.CloneOptions{
+		Progress:      progress,
 		URL:           repoUrl,
-		Auth:          auth,
+		Auth:          authMethod(auth),
 		ReferenceName: plumbing.ReferenceName(referenceName),
 		SingleBranch:  true,
+		Depth:         opts.Depth,
+		NoCheckout:    len(opts.SparsePaths) > 0,
 	})
 
 	if err != nil {
@@ -194,46 +220,180 @@ func cloneRepo(repoId string, repoUrl string, repoBranch string, repoDir string)
 		return nil, err
 	}
 
+	if len(opts.SparsePaths) > 0 || opts.Submodules {
+		err = checkoutBranch(ctx, r, repoBranch, auth, opts, progress)
+	} else {
+		err = resolveLfs(ctx, r, auth, opts, progress)
+	}
+
+	if err != nil {
+		os.RemoveAll(repoDir)
+		return nil, err
+	}
+
+	err = writeCloneOptions(repoDir, opts)
+
+	if err != nil {
+		return nil, err
+	}
+
 	return r, nil
 }
 
-func pullBranch(r *git.Repository, repoId string, repoBranch string) error {
-	dirPath := os.Getenv(SSH_ROOT) + "/" + repoId
-	auth, err := getPublicKey(dirPath)
+// pullBranch fetches the branch and force checks it out. Pulling through the
+// worktree would refuse to run once LFS objects replaced their pointers, and
+// would check out every path of a sparse clone.
+func pullBranch(ctx context.Context, r *git.Repository, repoId string, repoUrl string, repoBranch string, opts cloneOptions, progress ProgressReporter) error {
+	auth, err := repoAuth(repoId, repoUrl)
 
 	if err != nil {
 		log.Errorln(err)
 		return err
 	}
 
+	refSpec := config.RefSpec(fmt.Sprintf("+refs/heads/%[1]s:refs/remotes/origin/%[1]s", repoBranch))
+	err = r.FetchContext(ctx, &git.FetchOptions{
+		Progress:   progress,
+		RemoteName: "origin",
+		Auth:       authMethod(auth),
+		RefSpecs:   []config.RefSpec{refSpec},
+		Depth:      opts.Depth,
+		Force:      true,
+	})
+
+	if err != nil && err != git.NoErrAlreadyUpToDate {
+		log.Errorln(err)
+		return err
+	}
+
+	return checkoutBranch(ctx, r, repoBranch, auth, opts, progress)
+}
+
+// checkoutBranch checks out the fetched remote branch.
+func checkoutBranch(ctx context.Context, r *git.Repository, repoBranch string, auth *ssh.PublicKeys, opts cloneOptions, progress ProgressReporter) error {
+	remoteRef, err := r.Reference(plumbing.NewRemoteReferenceName("origin", repoBranch), true)
+
+	if err != nil {
+		return err
+	}
+
+	return checkoutCommit(ctx, r, repoBranch, remoteRef.Hash(), auth, opts, progress)
+}
+
+// checkoutCommit moves the local branch to the commit and checks it out,
+// limited to the sparse paths, then updates submodules and LFS objects.
+func checkoutCommit(ctx context.Context, r *git.Repository, repoBranch string, hash plumbing.Hash, auth *ssh.PublicKeys, opts cloneOptions, progress ProgressReporter) error {
+	branchRef := plumbing.NewHashReference(plumbing.NewBranchReferenceName(repoBranch), hash)
+
+	if err := r.Storer.SetReference(branchRef); err != nil {
+		return err
+	}
+
 	w, err := r.Worktree()
 
 	if err != nil {
-		log.Errorln(err)
 		return err
 	}
 
-	err = w.Pull(&git.PullOptions{
-		Progress:      os.Stdout,
-		RemoteName:    "origin",
-		Auth:          auth,
-		ReferenceName: plumbing.ReferenceName(fmt.Sprintf("refs/heads/%s", repoBranch)),
+	err = stageSparse(r, w, hash, opts.SparsePaths)
+
+	if err != nil {
+		return err
+	}
+
+	// The worktree checkout itself can not be interrupted, a sync cancelled
+	// after the fetch stops before it.
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	err = w.Checkout(&git.CheckoutOptions{
+		Branch: branchRef.Name(),
+		Force:  true,
 	})
 
 	if err != nil {
-		log.Errorln(err)
+		return err
+	}
+
+	if opts.Submodules {
+		progress.Phase(SYNC_PHASE_SUBMODULES)
+		err = updateSubmodules(ctx, w, auth, opts.SparsePaths)
 
-		if err == git.NoErrAlreadyUpToDate {
-			return nil
+		if err != nil {
+			return err
 		}
+	}
+
+	return resolveLfs(ctx, r, auth, opts, progress)
+}
+
+// stageSparse stages the commit without touching the worktree and marks the
+// entries outside the sparse paths to be skipped by the checkout. go-git's
+// own sparse checkout only skips paths already in the index, so a fresh clone
+// would check out everything, and it never clears the flags of paths added
+// later.
+func stageSparse(r *git.Repository, w *git.Worktree, hash plumbing.Hash, sparse []string) error {
+	err := w.Reset(&git.ResetOptions{Commit: hash, Mode: git.MixedReset})
+
+	if err != nil {
+		return err
+	}
 
+	idx, err := r.Storer.Index()
+
+	if err != nil {
 		return err
 	}
 
+	for _, entry := range idx.Entries {
+		entry.SkipWorktree = len(sparse) > 0 && !underPaths(entry.Name, sparse)
+	}
+
+	return r.Storer.SetIndex(idx)
+}
+
+// updateSubmodules initializes and updates the submodules of the worktree.
+// Sparse checkouts only update the submodules under their paths.
+func updateSubmodules(ctx context.Context, w *git.Worktree, auth *ssh.PublicKeys, sparsePaths []string) error {
+	submodules, err := w.Submodules()
+
+	if err != nil {
+		return err
+	}
+
+	for _, submodule := range submodules {
+		if len(sparsePaths) > 0 && !underPaths(submodule.Config().Path, sparsePaths) {
+			continue
+		}
+
+		err = submodule.UpdateContext(ctx, &git.SubmoduleUpdateOptions{
+			Init:              true,
+			RecurseSubmodules: git.DefaultSubmoduleRecursionDepth,
+			Auth:              authMethod(auth),
+		})
+
+		if err != nil {
+			return fmt.Errorf("submodule %s: %w", submodule.Config().Name, err)
+		}
+	}
+
 	return nil
 }
 
-func pullRepo(repoId string, repoUrl string, repoBranch string, repoDir string) (*git.Repository, error) {
+func underPaths(name string, paths []string) bool {
+	for _, p := range paths {
+		p = strings.Trim(p, "/")
+
+		if p == "" || name == p || strings.HasPrefix(name, p+"/") {
+			return true
+		}
+	}
+
+	return false
+}
+
+func pullRepo(ctx context.Context, repoId string, repoUrl string, repoBranch string, repoDir string, opts cloneOptions, progress ProgressReporter) (*git.Repository, error) {
 	log.Infof("git pull origin %s", repoBranch)
 	r, err := git.PlainOpen(repoDir)
 
@@ -250,14 +410,36 @@ func pullRepo(repoId string, repoUrl string, repoBranch string, repoDir string)
 	}
 
 	currentBranch := strings.TrimPrefix(string(h.Name()), "refs/heads/")
+	previous, _ := readCloneOptions(repoDir)
+	sameRemote := false
+
+	if remote, err := r.Remote("origin"); err == nil && len(remote.Config().URLs) > 0 {
+		sameRemote = remote.Config().URLs[0] == repoUrl
+	}
 
-	if currentBranch == repoBranch {
-		err := pullBranch(r, repoId, repoBranch)
+	// A changed URL, branch, depth or submodules setting clones again.
+	if sameRemote && currentBranch == repoBranch && previous.Depth == opts.Depth && previous.Submodules == opts.Submodules {
+		remoteHash, err := remoteHead(ctx, repoId, repoUrl, repoBranch)
+
+		if err != nil {
+			logError("failed to list remote refs", err)
+		} else if remoteHash == h.Hash() && previous.equal(opts) {
+			log.Infof("%s is up to date at %s", repoBranch, remoteHash)
+			return r, nil
+		}
+
+		err = pullBranch(ctx, r, repoId, repoUrl, repoBranch, opts, progress)
 
 		if err != nil {
 			log.Errorln(err)
 			return nil, err
 		}
+
+		err = writeCloneOptions(repoDir, opts)
+
+		if err != nil {
+			return nil, err
+		}
 	} else {
 		err := os.RemoveAll(repoDir)
 
@@ -266,7 +448,7 @@ func pullRepo(repoId string, repoUrl string, repoBranch string, repoDir string)
 			return nil, err
 		}
 
-		r, err = doSync(repoId, repoUrl, repoBranch, repoDir)
+		r, err = doSync(ctx, repoId, repoUrl, repoBranch, repoDir, opts, progress)
 
 		if err != nil {
 			log.Errorln(err)
@@ -277,6 +459,235 @@ func pullRepo(repoId string, repoUrl string, repoBranch string, repoDir string)
 	return r, nil
 }
 
+// acceptedHash returns the commit a rejected sync rolls back to: the
+// previous head, unless it is the rejected commit or fails the policy too,
+// then none.
+func acceptedHash(r *git.Repository, previousHash string, rejected plumbing.Hash, policy *SignaturePolicy) string {
+	if previousHash == "" || previousHash == rejected.String() {
+		return ""
+	}
+
+	commit, err := r.CommitObject(plumbing.NewHash(previousHash))
+
+	if err != nil || verifyCommit(commit, policy) != nil {
+		return ""
+	}
+
+	return previousHash
+}
+
+// rollbackRepo puts the checkout back on a previously accepted commit. A
+// checkout without one is removed so no rejected manifests are served.
+func rollbackRepo(ctx context.Context, r *git.Repository, repoId string, repoUrl string, repoBranch string, repoDir string, previousHash string, opts cloneOptions) error {
+	if previousHash == "" {
+		return os.RemoveAll(repoDir)
+	}
+
+	hash := plumbing.NewHash(previousHash)
+
+	if _, err := r.CommitObject(hash); err != nil {
+		log.Errorf("previous commit %s is gone, removing %s", previousHash, repoDir)
+		return os.RemoveAll(repoDir)
+	}
+
+	auth, err := repoAuth(repoId, repoUrl)
+
+	if err != nil {
+		return err
+	}
+
+	return checkoutCommit(ctx, r, repoBranch, hash, auth, opts, stdoutProgress{})
+}
+
+func readCloneOptions(repoDir string) (cloneOptions, error) {
+	var opts cloneOptions
+	contents, err := os.ReadFile(filepath.Join(repoDir, ".git", CLONE_OPTIONS_FILE))
+
+	if err != nil {
+		return opts, err
+	}
+
+	err = json.Unmarshal(contents, &opts)
+	return opts, err
+}
+
+func writeCloneOptions(repoDir string, opts cloneOptions) error {
+	contents, err := json.Marshal(opts)
+
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(filepath.Join(repoDir, ".git", CLONE_OPTIONS_FILE), contents, 0644)
+}
+
+func (o cloneOptions) equal(other cloneOptions) bool {
+	if o.Depth != other.Depth || o.Submodules != other.Submodules || o.Lfs != other.Lfs {
+		return false
+	}
+
+	if len(o.SparsePaths) != len(other.SparsePaths) {
+		return false
+	}
+
+	for i := range o.SparsePaths {
+		if o.SparsePaths[i] != other.SparsePaths[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// newCloneOptions reads the clone options of a sync request. Sparse paths
+// are sorted so the same set of applications gives the same checkout.
+func newCloneOptions(syncRequest *SyncRequest) cloneOptions {
+	var sparsePaths []string
+
+	for _, p := range syncRequest.SparsePaths {
+		p = strings.Trim(path.Clean("/"+p), "/")
+
+		if p == "" {
+			// An application at the repo root needs the whole tree.
+			sparsePaths = nil
+			break
+		}
+
+		if !contains(sparsePaths, p) {
+			sparsePaths = append(sparsePaths, p)
+		}
+	}
+
+	sort.Strings(sparsePaths)
+
+	return cloneOptions{
+		Depth:       int(syncRequest.Depth),
+		Submodules:  syncRequest.Submodules,
+		SparsePaths: sparsePaths,
+		Lfs:         syncRequest.Lfs,
+	}
+}
+
+// remoteHead lists the remote refs, which is much cheaper than a fetch, and
+// returns the hash the branch currently points to.
+func remoteHead(ctx context.Context, repoId string, repoUrl string, repoBranch string) (plumbing.Hash, error) {
+	auth, err := repoAuth(repoId, repoUrl)
+
+	if err != nil {
+		return plumbing.ZeroHash, err
+	}
+
+	remote := git.NewRemote(memory.NewStorage(), &config.RemoteConfig{
+		Name: "origin",
+		URLs: []string{repoUrl},
+	})
+
+	refs, err := remote.ListContext(ctx, &git.ListOptions{Auth: authMethod(auth)})
+
+	if err != nil {
+		return plumbing.ZeroHash, err
+	}
+
+	referenceName := plumbing.NewBranchReferenceName(repoBranch)
+
+	for _, ref := range refs {
+		if ref.Name() == referenceName {
+			return ref.Hash(), nil
+		}
+	}
+
+	return plumbing.ZeroHash, fmt.Errorf("branch %s not found on remote", repoBranch)
+}
+
+func headHash(repoDir string) string {
+	r, err := git.PlainOpen(repoDir)
+
+	if err != nil {
+		return ""
+	}
+
+	h, err := r.Head()
+
+	if err != nil {
+		return ""
+	}
+
+	return h.Hash().String()
+}
+
+// parseManifestFile decodes a data, schema or uischema file. Files with a
+// yaml extension are read as YAML, everything else as JSON.
+func parseManifestFile(name string, contents []byte) (map[string]interface{}, error) {
+	var result map[string]interface{}
+	var err error
+
+	if contains(validYamlExt, filepath.Ext(name)) {
+		err = yaml.Unmarshal(contents, &result)
+	} else {
+		err = json.Unmarshal(contents, &result)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// parseManifest parses a manifest file. Data files may hold several
+// documents, the Job and the objects created next to it.
+func parseManifest(name string, contents []byte, workload string) (*manifestFile, error) {
+	if strings.TrimSuffix(name, filepath.Ext(name)) != "data" {
+		result, err := parseManifestFile(name, contents)
+
+		if err != nil {
+			return nil, err
+		}
+
+		return &manifestFile{Name: name, Contents: contents, Data: result}, nil
+	}
+
+	parsed, err := manifestPkg.ParseWorkload(contents, workload)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &manifestFile{Name: name, Contents: contents, Data: parsed.Job, Resources: parsed.Resources}, nil
+}
+
+func renderResponse(manifest string, parsed *manifestPkg.Manifest) (*RenderResponse, error) {
+	job, err := structpb.NewStruct(jsonCompatible(parsed.Job))
+
+	if err != nil {
+		return nil, err
+	}
+
+	resources, err := resourceStructs(parsed.Resources)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &RenderResponse{Job: job, Resources: resources, Manifest: manifest}, nil
+}
+
+func resourceStructs(resources []map[string]interface{}) ([]*structpb.Struct, error) {
+	var structs []*structpb.Struct
+
+	for _, resource := range resources {
+		details, err := structpb.NewStruct(jsonCompatible(resource))
+
+		if err != nil {
+			return nil, err
+		}
+
+		structs = append(structs, details)
+	}
+
+	return structs, nil
+}
+
 func contains(s []string, str string) bool {
 	for _, v := range s {
 		if v == str {
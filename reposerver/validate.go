@@ -0,0 +1,424 @@
+package reposerver
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	manifestPkg "github.com/kubefill/kubefill/pkg/manifest"
+	"github.com/santhosh-tekuri/jsonschema/v5"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"helm.sh/helm/v3/pkg/chart/loader"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	SEVERITY_ERROR   = "error"
+	SEVERITY_WARNING = "warning"
+)
+
+var yamlErrorLine = regexp.MustCompile(`line (\d+)`)
+
+// jobManifest mirrors the data file, which is submitted as client.JobConfig.
+type jobManifest struct {
+	ObjectMeta metav1.ObjectMeta `json:"metadata"`
+	Spec       batchv1.JobSpec   `json:"spec"`
+	Labels     map[string]string `json:"labels"`
+}
+
+func (s RepoService) ValidateManifests(ctx context.Context, manifestsRequest *ManifestsRequest) (*ValidateManifestsResponse, error) {
+	release, err := s.readPath(ctx, manifestsRequest.Path)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer release()
+
+	manifestDir := filepath.Join(os.Getenv(REPO_ROOT), manifestsRequest.Path)
+
+	if info, err := os.Stat(manifestDir); err != nil || !info.IsDir() {
+		return nil, status.Errorf(codes.NotFound, "manifest directory %s not found", manifestsRequest.Path)
+	}
+
+	if isChart(manifestDir) {
+		return validateChart(manifestDir, manifestsRequest.Workload), nil
+	}
+
+	if isKustomize(manifestDir) {
+		return validateKustomize(manifestDir, manifestsRequest.Workload), nil
+	}
+
+	if isTemplate(manifestDir) {
+		return validateTemplate(manifestDir), nil
+	}
+
+	manifests, issues, err := readManifests(manifestDir, manifestsRequest.Workload)
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, name := range manifestFiles {
+		if _, ok := manifests[name]; ok {
+			continue
+		}
+
+		if _, failed := failedFiles(issues)[name]; failed {
+			continue
+		}
+
+		severity := SEVERITY_ERROR
+
+		if name == "uischema" {
+			severity = SEVERITY_WARNING
+		}
+
+		issues = append(issues, &ManifestIssue{File: name, Severity: severity, Message: "file is missing"})
+	}
+
+	if data, ok := manifests["data"]; ok {
+		issues = append(issues, validateData(data, manifestsRequest.Workload)...)
+	}
+
+	schema, ok := manifests["schema"]
+
+	if ok {
+		issues = append(issues, validateSchema(schema)...)
+	}
+
+	if uiSchema, ok := manifests["uischema"]; ok && schema != nil {
+		issues = append(issues, validateUiSchema(uiSchema, schema)...)
+	}
+
+	return validationResponse(issues), nil
+}
+
+// validateChart checks the values schema and that the chart renders a
+// runnable Job with its default values.
+func validateChart(dir string, workload string) *ValidateManifestsResponse {
+	chrt, err := loader.Load(dir)
+
+	if err != nil {
+		return validationResponse([]*ManifestIssue{{File: "Chart.yaml", Severity: SEVERITY_ERROR, Message: err.Error()}})
+	}
+
+	var issues []*ManifestIssue
+	var schema map[string]interface{}
+
+	if len(chrt.Schema) == 0 {
+		issues = append(issues, &ManifestIssue{File: "values.schema.json", Severity: SEVERITY_ERROR, Message: "file is missing"})
+	} else if err := json.Unmarshal(chrt.Schema, &schema); err != nil {
+		issues = append(issues, &ManifestIssue{
+			File:     "values.schema.json",
+			Line:     int32(errorLine(chrt.Schema, err)),
+			Severity: SEVERITY_ERROR,
+			Message:  err.Error(),
+		})
+	} else {
+		issues = append(issues, validateSchema(&manifestFile{Name: "values.schema.json", Contents: chrt.Schema, Data: schema})...)
+	}
+
+	manifests, parseIssues, err := readManifests(dir, "")
+
+	if err != nil {
+		return validationResponse(append(issues, &ManifestIssue{File: dir, Severity: SEVERITY_ERROR, Message: err.Error()}))
+	}
+
+	issues = append(issues, parseIssues...)
+
+	if uiSchema, ok := manifests["uischema"]; ok && schema != nil {
+		issues = append(issues, validateUiSchema(uiSchema, &manifestFile{Name: "values.schema.json", Data: schema})...)
+	}
+
+	manifest, err := renderChart(chrt, map[string]interface{}{}, "", "")
+
+	if err != nil {
+		return validationResponse(append(issues, &ManifestIssue{File: "templates", Severity: SEVERITY_ERROR, Message: err.Error()}))
+	}
+
+	parsed, err := manifestPkg.ParseWorkload([]byte(manifest), workload)
+
+	if err != nil {
+		return validationResponse(append(issues, &ManifestIssue{File: "templates", Severity: SEVERITY_ERROR, Message: err.Error()}))
+	}
+
+	return validationResponse(append(issues, validateData(&manifestFile{Name: "templates", Data: parsed.Job}, workload)...))
+}
+
+func validationResponse(issues []*ManifestIssue) *ValidateManifestsResponse {
+	resp := ValidateManifestsResponse{Valid: true, Issues: issues}
+
+	for _, issue := range issues {
+		if issue.Severity == SEVERITY_ERROR {
+			resp.Valid = false
+		}
+	}
+
+	return &resp
+}
+
+// readManifests reads and parses the data, schema and uischema files of dir.
+// Files that fail to parse are left out of the result and reported as issues.
+func readManifests(dir string, workload string) (map[string]*manifestFile, []*ManifestIssue, error) {
+	files, err := os.ReadDir(dir)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	manifests := make(map[string]*manifestFile)
+	var issues []*ManifestIssue
+
+	for _, file := range files {
+		if file.IsDir() {
+			continue
+		}
+
+		fullFileName := file.Name()
+		baseFileName := strings.TrimSuffix(fullFileName, filepath.Ext(fullFileName))
+
+		if !contains(manifestFiles, baseFileName) {
+			continue
+		}
+
+		contents, err := os.ReadFile(filepath.Join(dir, fullFileName))
+
+		if err != nil {
+			return nil, nil, err
+		}
+
+		manifest, err := parseManifest(fullFileName, contents, workload)
+
+		if err != nil {
+			issues = append(issues, &ManifestIssue{
+				File:     fullFileName,
+				Line:     int32(errorLine(contents, err)),
+				Severity: SEVERITY_ERROR,
+				Message:  err.Error(),
+			})
+			continue
+		}
+
+		manifests[baseFileName] = manifest
+	}
+
+	return manifests, issues, nil
+}
+
+// errorLine finds the line a parse error points at, or 0 when unknown.
+func errorLine(contents []byte, err error) int {
+	var syntaxError *json.SyntaxError
+	var unmarshalTypeError *json.UnmarshalTypeError
+
+	switch {
+	case errors.As(err, &syntaxError):
+		return bytes.Count(contents[:syntaxError.Offset], []byte("\n")) + 1
+	case errors.As(err, &unmarshalTypeError):
+		return bytes.Count(contents[:unmarshalTypeError.Offset], []byte("\n")) + 1
+	}
+
+	match := yamlErrorLine.FindStringSubmatch(err.Error())
+
+	if match != nil {
+		line, _ := strconv.Atoi(match[1])
+		return line
+	}
+
+	return 0
+}
+
+func failedFiles(issues []*ManifestIssue) map[string]bool {
+	failed := make(map[string]bool)
+
+	for _, issue := range issues {
+		failed[strings.TrimSuffix(issue.File, filepath.Ext(issue.File))] = true
+	}
+
+	return failed
+}
+
+func formatIssues(issues []*ManifestIssue) string {
+	var messages []string
+
+	for _, issue := range issues {
+		if issue.Line > 0 {
+			messages = append(messages, fmt.Sprintf("%s:%d: %s", issue.File, issue.Line, issue.Message))
+		} else {
+			messages = append(messages, fmt.Sprintf("%s: %s", issue.File, issue.Message))
+		}
+	}
+
+	return strings.Join(messages, "; ")
+}
+
+// validateData checks that the data file decodes into a batch JobSpec and
+// that the job template is runnable.
+func validateData(data *manifestFile, workload string) []*ManifestIssue {
+	var issues []*ManifestIssue
+
+	// Other workloads are validated by the API server when they are created.
+	if !manifestPkg.IsJob(workload) {
+		return nil
+	}
+
+	issue := func(severity string, message string) {
+		issues = append(issues, &ManifestIssue{File: data.Name, Severity: severity, Message: message})
+	}
+
+	raw, err := json.Marshal(data.Data)
+
+	if err != nil {
+		issue(SEVERITY_ERROR, err.Error())
+		return issues
+	}
+
+	var manifest jobManifest
+	err = json.Unmarshal(raw, &manifest)
+
+	if err != nil {
+		issue(SEVERITY_ERROR, fmt.Sprintf("does not decode into a batch/v1 JobSpec: %v", err))
+		return issues
+	}
+
+	strict := json.NewDecoder(bytes.NewReader(raw))
+	strict.DisallowUnknownFields()
+
+	if err := strict.Decode(&jobManifest{}); err != nil {
+		issue(SEVERITY_WARNING, strings.TrimPrefix(err.Error(), "json: "))
+	}
+
+	podSpec := manifest.Spec.Template.Spec
+
+	if len(podSpec.Containers) == 0 {
+		issue(SEVERITY_ERROR, "spec.template.spec.containers must contain at least one container")
+	}
+
+	for i, container := range podSpec.Containers {
+		if container.Image == "" {
+			issue(SEVERITY_ERROR, fmt.Sprintf("spec.template.spec.containers[%d].image is required", i))
+		}
+	}
+
+	restartPolicy := podSpec.RestartPolicy
+
+	if restartPolicy != corev1.RestartPolicyNever && restartPolicy != corev1.RestartPolicyOnFailure {
+		issue(SEVERITY_ERROR, "spec.template.spec.restartPolicy must be Never or OnFailure")
+	}
+
+	return issues
+}
+
+// validateSchema compiles the schema, which validates it against its meta
+// schema. Schemas without $schema are treated as draft-07 like the UI does.
+func validateSchema(schema *manifestFile) []*ManifestIssue {
+	raw, err := json.Marshal(schema.Data)
+
+	if err != nil {
+		return []*ManifestIssue{{File: schema.Name, Severity: SEVERITY_ERROR, Message: err.Error()}}
+	}
+
+	compiler := jsonschema.NewCompiler()
+	compiler.Draft = jsonschema.Draft7
+	err = compiler.AddResource(schema.Name, bytes.NewReader(raw))
+
+	if err == nil {
+		_, err = compiler.Compile(schema.Name)
+	}
+
+	if err == nil {
+		return nil
+	}
+
+	var validationError *jsonschema.ValidationError
+
+	if !errors.As(err, &validationError) {
+		return []*ManifestIssue{{File: schema.Name, Severity: SEVERITY_ERROR, Message: err.Error()}}
+	}
+
+	var issues []*ManifestIssue
+
+	for _, cause := range validationError.BasicOutput().Errors {
+		if cause.InstanceLocation == "" && len(validationError.Causes) > 0 {
+			continue
+		}
+
+		issues = append(issues, &ManifestIssue{
+			File:     schema.Name,
+			Severity: SEVERITY_ERROR,
+			Message:  fmt.Sprintf("%s: %s", instanceLocation(cause.InstanceLocation), cause.Error),
+		})
+	}
+
+	return issues
+}
+
+func instanceLocation(location string) string {
+	if location == "" {
+		return "/"
+	}
+
+	return location
+}
+
+// validateUiSchema reports uischema keys that do not name a property of the
+// schema. Keys starting with ui: are rjsf directives and always allowed.
+func validateUiSchema(uiSchema *manifestFile, schema *manifestFile) []*ManifestIssue {
+	var issues []*ManifestIssue
+	var walk func(ui map[string]interface{}, schema map[string]interface{}, path string)
+
+	walk = func(ui map[string]interface{}, schema map[string]interface{}, path string) {
+		properties, _ := schema["properties"].(map[string]interface{})
+		keys := make([]string, 0, len(ui))
+
+		for key := range ui {
+			keys = append(keys, key)
+		}
+
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			if strings.HasPrefix(key, "ui:") {
+				continue
+			}
+
+			if key == "items" {
+				if items, ok := schema["items"].(map[string]interface{}); ok {
+					if child, ok := ui[key].(map[string]interface{}); ok {
+						walk(child, items, path+"/items")
+					}
+					continue
+				}
+			}
+
+			property, ok := properties[key].(map[string]interface{})
+
+			if !ok {
+				issues = append(issues, &ManifestIssue{
+					File:     uiSchema.Name,
+					Severity: SEVERITY_WARNING,
+					Message:  fmt.Sprintf("%s/%s does not match a schema property", path, key),
+				})
+				continue
+			}
+
+			if child, ok := ui[key].(map[string]interface{}); ok {
+				walk(child, property, path+"/"+key)
+			}
+		}
+	}
+
+	walk(uiSchema.Data, schema.Data, "")
+	return issues
+}
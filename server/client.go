@@ -48,10 +48,16 @@ var upgrader = websocket.Upgrader{
 }
 
 type Client struct {
-	Id   string
-	hub  *Hub
-	conn *websocket.Conn
-	send chan []byte
+	Id    string
+	hub   *Hub
+	syncs *syncTracker
+	conn  *websocket.Conn
+	send  chan []byte
+}
+
+func getTopic(event string) string {
+	eventParts := strings.Split(event, ":")
+	return eventParts[0]
 }
 
 func getAction(event string) string {
@@ -102,6 +108,14 @@ func (c *Client) readPump() {
 
 		action := getAction(incomingMessage.Event)
 
+		if getTopic(incomingMessage.Event) == SYNC_TOPIC {
+			if action == "subscribe" && !c.syncs.subscribe(c) {
+				log.Warnf("sync %s not found", c.Id)
+			}
+
+			continue
+		}
+
 		if action == "subscribe" {
 			_, ok := c.hub.rooms[c.Id]
 
@@ -0,0 +1,203 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/kubefill/kubefill/pkg/application"
+	"github.com/kubefill/kubefill/pkg/db"
+	"github.com/kubefill/kubefill/reposerver"
+	log "github.com/sirupsen/logrus"
+)
+
+func normalizeManifestPath(manifestPath string) string {
+	return strings.Trim(path.Clean("/"+manifestPath), "/")
+}
+
+// discoverApplications lists the manifest directories of the repo and marks
+// the ones that already back an application. It also returns which paths of
+// the applications are still directories of the tree.
+func (s *Server) discoverApplications(ctx context.Context, rp reposerver.RepoServiceClient, repo db.Repo) (DiscoverHttpResponse, []db.Application, map[string]bool, error) {
+	resp := DiscoverHttpResponse{Applications: []DiscoveredAppHttpResponse{}, Issues: []*reposerver.ManifestIssue{}}
+	apps, err := s.applicationService.ListByRepoId(repo.ID)
+
+	if err != nil {
+		return resp, nil, nil, err
+	}
+
+	existing := make(map[string]uint)
+	var paths []string
+
+	for _, app := range apps {
+		existing[normalizeManifestPath(app.ManifestPath)] = app.ID
+		paths = append(paths, normalizeManifestPath(app.ManifestPath))
+	}
+
+	discovered, err := rp.DiscoverApplications(ctx, &reposerver.DiscoverRequest{RepoUrl: repo.Url, RepoId: strconv.FormatInt(int64(repo.ID), 10), Branch: repo.Branch, Paths: paths})
+
+	if err != nil {
+		return resp, nil, nil, err
+	}
+
+	present := make(map[string]bool)
+
+	for _, p := range discovered.Present {
+		present[p] = true
+	}
+
+	resp.Hash = discovered.Hash
+	resp.Issues = append(resp.Issues, discovered.Issues...)
+
+	for _, app := range discovered.Applications {
+		resp.Applications = append(resp.Applications, DiscoveredAppHttpResponse{
+			Name:          app.Name,
+			Path:          app.Path,
+			ApplicationID: existing[app.Path],
+		})
+	}
+
+	return resp, apps, present, nil
+}
+
+// reconcileApplications creates an application for every discovered path in
+// paths that has none yet, or for all of them when paths is empty. With prune
+// set, discovered applications whose directory is gone from the tree are
+// removed as well; applications created by hand are never touched. A
+// directory whose manifests stopped parsing keeps its application, the
+// problems are returned instead.
+func (s *Server) reconcileApplications(ctx context.Context, rp reposerver.RepoServiceClient, repo db.Repo, paths []string, prune bool) (ReconcileHttpResponse, error) {
+	resp := ReconcileHttpResponse{Created: []application.Application{}, Removed: []uint{}, Problems: []string{}}
+	discovered, apps, present, err := s.discoverApplications(ctx, rp, repo)
+
+	if err != nil {
+		return resp, err
+	}
+
+	for _, issue := range discovered.Issues {
+		resp.Problems = append(resp.Problems, fmt.Sprintf("%s: %s", issue.File, issue.Message))
+	}
+
+	wanted := make(map[string]bool)
+
+	for _, p := range paths {
+		wanted[normalizeManifestPath(p)] = true
+	}
+
+	found := make(map[string]bool)
+
+	for _, app := range discovered.Applications {
+		found[app.Path] = true
+
+		if app.ApplicationID != 0 || (len(wanted) > 0 && !wanted[app.Path]) {
+			continue
+		}
+
+		resp.Created = append(resp.Created, s.applicationService.Create(application.Application{
+			Name:         app.Name,
+			RepoID:       repo.ID,
+			ManifestPath: app.Path,
+			Discovered:   true,
+		}))
+	}
+
+	if len(resp.Created) > 0 {
+		s.syncSparse(repo.ID)
+	}
+
+	if !prune {
+		return resp, nil
+	}
+
+	for _, app := range apps {
+		appPath := normalizeManifestPath(app.ManifestPath)
+
+		if !app.Discovered || found[appPath] {
+			continue
+		}
+
+		if present[appPath] {
+			resp.Problems = append(resp.Problems, fmt.Sprintf("%s: no valid manifests anymore, application %s is kept", displayPath(appPath), app.Name))
+			continue
+		}
+
+		err := s.applicationService.Delete(app)
+
+		if err != nil {
+			return resp, err
+		}
+
+		resp.Removed = append(resp.Removed, app.ID)
+	}
+
+	return resp, nil
+}
+
+// afterSync applies the config file of the freshly synced tree and keeps the
+// applications of auto discovering repos in line with its directories.
+func (s *Server) afterSync(rp reposerver.RepoServiceClient, repoId uint) {
+	repo, err := s.repoService.Get(repoId)
+
+	if err != nil {
+		log.Errorln(err)
+		return
+	}
+
+	err = s.reconcileRepoConfig(context.Background(), rp, repo)
+
+	if err != nil {
+		log.Errorf("failed to apply %s of repo %d: %v", application.CONFIG_FILE, repoId, err)
+	}
+
+	if !repo.AutoDiscover {
+		return
+	}
+
+	resp, err := s.reconcileApplications(context.Background(), rp, repo, nil, true)
+
+	if err != nil {
+		log.Errorf("failed to discover applications of repo %d: %v", repoId, err)
+		return
+	}
+
+	if err := s.recordDiscoveryErrors(repoId, resp.Problems); err != nil {
+		log.Errorln(err)
+	}
+
+	if len(resp.Created) > 0 || len(resp.Removed) > 0 {
+		log.Infof("repo %d: created %d and removed %d discovered applications", repoId, len(resp.Created), len(resp.Removed))
+	}
+}
+
+// recordDiscoveryErrors stores the problems of the last discovery on the
+// repo, leaving it untouched when they did not change. The repo is read
+// again, applying the config file may have updated it.
+func (s *Server) recordDiscoveryErrors(repoId uint, problems []string) error {
+	repo, err := s.repoService.Get(repoId)
+
+	if err != nil {
+		return err
+	}
+
+	if len(problems) == 0 {
+		problems = nil
+	}
+
+	if reflect.DeepEqual(repo.DiscoveryErrors, problems) {
+		return nil
+	}
+
+	repo.DiscoveryErrors = problems
+	return s.repoService.Update(repo)
+}
+
+func displayPath(manifestPath string) string {
+	if manifestPath == "" {
+		return "."
+	}
+
+	return manifestPath
+}
@@ -19,13 +19,14 @@ import (
 	"github.com/kubefill/kubefill/pkg/application"
 	"github.com/kubefill/kubefill/pkg/auth"
 	"github.com/kubefill/kubefill/pkg/client"
+	"github.com/kubefill/kubefill/pkg/db"
 	"github.com/kubefill/kubefill/pkg/job"
 	repoPkg "github.com/kubefill/kubefill/pkg/repo"
 	"github.com/kubefill/kubefill/pkg/secret"
+	"github.com/kubefill/kubefill/pkg/webhook"
 	"github.com/kubefill/kubefill/reposerver"
 	log "github.com/sirupsen/logrus"
-	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -40,7 +41,7 @@ func (s *Server) apiRoot() http.HandlerFunc {
 
 func (s *Server) reposHandler(service *repoPkg.Service) http.HandlerFunc {
 	return func(rw http.ResponseWriter, r *http.Request) {
-		conn, err := grpc.Dial(s.ServerConfig.RepoServerAddress, grpc.WithTransportCredentials(insecure.NewCredentials()))
+		conn, err := s.dialRepoServer()
 
 		if err != nil {
 			log.Errorln(err)
@@ -70,9 +71,51 @@ func (s *Server) reposHandler(service *repoPkg.Service) http.HandlerFunc {
 				return
 			}
 
+			if newRepoPayload.Poll_Interval < repoPkg.POLL_DISABLED {
+				JSONError(rw, errorResp{Message: "poll_interval must be -1 (disabled), 0 (default) or a number of minutes"}, http.StatusBadRequest)
+				return
+			}
+
+			if _, err := reposerver.ParseGitURL(newRepoPayload.Url); err != nil {
+				JSONError(rw, errorResp{Message: err.Error()}, http.StatusBadRequest)
+				return
+			}
+
+			if newRepoPayload.Depth < 0 {
+				JSONError(rw, errorResp{Message: "depth must be 0 (full history) or a number of commits"}, http.StatusBadRequest)
+				return
+			}
+
+			err = repoPkg.ValidateSignaturePolicy(newRepoPayload.SignaturePolicy)
+
+			if err != nil {
+				JSONError(rw, errorResp{Message: err.Error()}, http.StatusBadRequest)
+				return
+			}
+
+			branch, err := resolveBranch(r.Context(), rp, newRepoPayload.Url, "", newRepoPayload.Ssh_Private_Key, newRepoPayload.Branch)
+
+			if err != nil {
+				JSONError(rw, errorResp{Message: status.Convert(err).Message()}, grpcHttpStatus(err))
+				return
+			}
+
 			var newRepo repoPkg.Repo
 			newRepo.Url = newRepoPayload.Url
-			newRepo.Branch = newRepoPayload.Branch
+			newRepo.Branch = branch
+			newRepo.PollInterval = newRepoPayload.Poll_Interval
+			newRepo.CloneOptions = newRepoPayload.CloneOptions
+			newRepo.SignaturePolicy = newRepoPayload.SignaturePolicy
+
+			if len(newRepoPayload.Webhook_Secret) > 0 {
+				newRepo.WebhookSecret, err = s.keyring.encrypt(newRepoPayload.Webhook_Secret)
+
+				if err != nil {
+					JSONError(rw, errorResp{Message: err.Error()}, http.StatusInternalServerError)
+					return
+				}
+			}
+
 			repo := service.Create(newRepo)
 
 			if len(newRepoPayload.Ssh_Private_Key) > 0 {
@@ -101,7 +144,7 @@ func (s *Server) reposHandler(service *repoPkg.Service) http.HandlerFunc {
 
 func (s *Server) repoHandler(repoService *repoPkg.Service) http.HandlerFunc {
 	return func(rw http.ResponseWriter, r *http.Request) {
-		conn, err := grpc.Dial(s.ServerConfig.RepoServerAddress, grpc.WithTransportCredentials(insecure.NewCredentials()))
+		conn, err := s.dialRepoServer()
 
 		if err != nil {
 			log.Errorln(err)
@@ -154,17 +197,25 @@ func (s *Server) repoHandler(repoService *repoPkg.Service) http.HandlerFunc {
 			action := vars["action"]
 
 			if action == "sync" {
-				message := reposerver.SyncRequest{Repo: repo.Url, Branch: repo.Branch, RepoId: strconv.FormatInt(int64(repo.ID), 10)}
-				resp, err := rp.Sync(context.Background(), &message)
+				syncId, started := s.startSync(repo)
+				resp := SyncHttpResponse{SyncId: syncId}
+				code := http.StatusAccepted
+
+				if !started {
+					resp.Message = "sync already in progress"
+					code = http.StatusConflict
+				}
+
+				respBytes, err := json.Marshal(resp)
 
 				if err != nil {
 					JSONError(rw, errorResp{Message: err.Error()}, http.StatusInternalServerError)
 					return
 				}
 
-				repo.Commit = resp.Commit
-				repo.Hash = resp.Hash
-				repoService.Update(repo)
+				rw.WriteHeader(code)
+				io.WriteString(rw, string(respBytes))
+				return
 			}
 
 			repoBytes, err := json.Marshal(repo)
@@ -195,8 +246,58 @@ func (s *Server) repoHandler(repoService *repoPkg.Service) http.HandlerFunc {
 				return
 			}
 
+			if updateRepoPayload.Poll_Interval < repoPkg.POLL_DISABLED {
+				JSONError(rw, errorResp{Message: "poll_interval must be -1 (disabled), 0 (default) or a number of minutes"}, http.StatusBadRequest)
+				return
+			}
+
+			if _, err := reposerver.ParseGitURL(updateRepoPayload.Url); err != nil {
+				JSONError(rw, errorResp{Message: err.Error()}, http.StatusBadRequest)
+				return
+			}
+
+			if updateRepoPayload.Depth < 0 {
+				JSONError(rw, errorResp{Message: "depth must be 0 (full history) or a number of commits"}, http.StatusBadRequest)
+				return
+			}
+
+			err = repoPkg.ValidateSignaturePolicy(updateRepoPayload.SignaturePolicy)
+
+			if err != nil {
+				JSONError(rw, errorResp{Message: err.Error()}, http.StatusBadRequest)
+				return
+			}
+
+			// The remote is only listed when what the repo tracks changes, so
+			// other settings can be edited while it is unreachable.
+			if updateRepoPayload.Url != repo.Url || updateRepoPayload.Branch != repo.Branch || len(updateRepoPayload.Ssh_Private_Key) > 0 {
+				repo.Branch, err = resolveBranch(r.Context(), rp, updateRepoPayload.Url, repoId, updateRepoPayload.Ssh_Private_Key, updateRepoPayload.Branch)
+
+				if err != nil {
+					JSONError(rw, errorResp{Message: status.Convert(err).Message()}, grpcHttpStatus(err))
+					return
+				}
+			}
+
 			repo.Url = updateRepoPayload.Url
-			repo.Branch = updateRepoPayload.Branch
+			repo.PollInterval = updateRepoPayload.Poll_Interval
+			repo.CloneOptions = updateRepoPayload.CloneOptions
+			repo.SignaturePolicy = updateRepoPayload.SignaturePolicy
+
+			if len(updateRepoPayload.Webhook_Secret) > 0 {
+				repo.WebhookSecret, err = s.keyring.encrypt(updateRepoPayload.Webhook_Secret)
+
+				if err != nil {
+					JSONError(rw, errorResp{Message: err.Error()}, http.StatusInternalServerError)
+					return
+				}
+			}
+
+			if len(updateRepoPayload.Ssh_Private_Key) > 0 {
+				repo.DeployKey = ""
+				repo.PendingDeployKey = ""
+			}
+
 			repoService.Update(repo)
 
 			if len(updateRepoPayload.Ssh_Private_Key) > 0 {
@@ -210,14 +311,22 @@ func (s *Server) repoHandler(repoService *repoPkg.Service) http.HandlerFunc {
 			}
 
 			repoBytes, err := json.Marshal(repoPkg.Repo{
-				Id:         int(repo.ID),
-				Url:        repo.Url,
-				Commit:     repo.Commit,
-				Hash:       repo.Hash,
-				Branch:     repo.Branch,
-				Created_At: repo.CreatedAt.String(),
-				Updated_At: repo.UpdatedAt.String(),
-				Deleted_At: repo.DeletedAt.Time.String(),
+				Id:               int(repo.ID),
+				Url:              repo.Url,
+				Commit:           repo.Commit,
+				Hash:             repo.Hash,
+				Branch:           repo.Branch,
+				PollInterval:     repo.PollInterval,
+				CloneOptions:     repo.CloneOptions,
+				SignaturePolicy:  repo.SignaturePolicy,
+				PolicyFailure:    repo.PolicyFailure,
+				DeployKey:        repo.DeployKey,
+				PendingDeployKey: repo.PendingDeployKey,
+				ConfigErrors:     repo.ConfigErrors,
+				DiscoveryErrors:  repo.DiscoveryErrors,
+				Created_At:       repo.CreatedAt.String(),
+				Updated_At:       repo.UpdatedAt.String(),
+				Deleted_At:       repo.DeletedAt.Time.String(),
 			})
 
 			if err != nil {
@@ -235,25 +344,420 @@ func (s *Server) repoHandler(repoService *repoPkg.Service) http.HandlerFunc {
 				} else {
 					JSONError(rw, errorResp{Message: err.Error()}, http.StatusInternalServerError)
 				}
-				return
+				return
+			}
+
+			cascade := false
+
+			if value := r.URL.Query().Get("cascade"); value != "" {
+				cascade, err = strconv.ParseBool(value)
+
+				if err != nil {
+					JSONError(rw, errorResp{Message: fmt.Sprintf("invalid cascade %q", value)}, http.StatusBadRequest)
+					return
+				}
+			}
+
+			apps, err := s.applicationService.ListByRepoId(repo.ID)
+
+			if err != nil {
+				JSONError(rw, errorResp{Message: err.Error()}, http.StatusInternalServerError)
+				return
+			}
+
+			if len(apps) > 0 && !cascade {
+				names := make([]string, len(apps))
+
+				for i, app := range apps {
+					names[i] = app.Name
+				}
+
+				JSONError(rw, errorResp{Message: fmt.Sprintf("repo is used by applications %s, delete them first or pass cascade=true", strings.Join(names, ", "))}, http.StatusConflict)
+				return
+			}
+
+			if cascade {
+				err = repoService.DeleteCascade(repo)
+			} else {
+				err = repoService.Delete(repo)
+			}
+
+			if err != nil {
+				JSONError(rw, errorResp{Message: err.Error()}, http.StatusInternalServerError)
+				return
+			}
+
+			// The rows go first, so a failed delete never leaves a repo
+			// without its keys. Checkouts and keys the reposerver can not
+			// remove now, while a sync runs, are left for the periodic prune.
+			message := reposerver.RemoveRepoRequest{RepoId: strconv.FormatInt(int64(repo.ID), 10)}
+			_, err = rp.RemoveRepo(r.Context(), &message)
+
+			if err != nil {
+				log.Errorf("Leaving checkouts of deleted repo %d to the prune: %s", repo.ID, status.Convert(err).Message())
+			}
+
+			for _, app := range apps {
+				log.Infof("Deleted application %d with repo %d", app.ID, repo.ID)
+			}
+
+			http.Error(rw, "", http.StatusNoContent)
+		default:
+			JSONError(rw, errorResp{Message: "Something went wrong..."}, http.StatusInternalServerError)
+		}
+	}
+}
+
+// repoDeployKeyHandler generates a deploy key for a repo, or rotates it. A
+// rotated key stays pending, and the previous key in use, until it is
+// confirmed to read the repo.
+func (s *Server) repoDeployKeyHandler(repoService *repoPkg.Service) http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "POST":
+			conn, err := s.dialRepoServer()
+
+			if err != nil {
+				JSONError(rw, errorResp{Message: err.Error()}, http.StatusInternalServerError)
+				return
+			}
+
+			defer conn.Close()
+
+			rp := reposerver.NewRepoServiceClient(conn)
+			vars := mux.Vars(r)
+			idAsUInt, _ := strconv.ParseUint(vars["id"], 10, 32)
+			repo, err := repoService.Get(uint(idAsUInt))
+
+			if err != nil {
+				if err.Error() == "record not found" {
+					JSONError(rw, errorResp{Message: err.Error()}, http.StatusNotFound)
+				} else {
+					JSONError(rw, errorResp{Message: err.Error()}, http.StatusInternalServerError)
+				}
+				return
+			}
+
+			repoId := strconv.FormatInt(int64(repo.ID), 10)
+
+			if vars["action"] == "confirm" {
+				resp, err := rp.ConfirmDeployKey(r.Context(), &reposerver.ConfirmDeployKeyRequest{RepoId: repoId, Repo: repo.Url})
+
+				if err != nil {
+					JSONError(rw, errorResp{Message: status.Convert(err).Message()}, grpcHttpStatus(err))
+					return
+				}
+
+				repo.DeployKey = resp.PublicKey
+				repo.PendingDeployKey = ""
+			} else {
+				resp, err := rp.GenerateDeployKey(r.Context(), &reposerver.GenerateDeployKeyRequest{RepoId: repoId})
+
+				if err != nil {
+					JSONError(rw, errorResp{Message: status.Convert(err).Message()}, grpcHttpStatus(err))
+					return
+				}
+
+				if resp.Pending {
+					repo.PendingDeployKey = resp.PublicKey
+				} else {
+					repo.DeployKey = resp.PublicKey
+				}
+			}
+
+			err = repoService.Update(repo)
+
+			if err != nil {
+				JSONError(rw, errorResp{Message: err.Error()}, http.StatusInternalServerError)
+				return
+			}
+
+			respBytes, err := json.Marshal(DeployKeyHttpResponse{
+				DeployKey:        repo.DeployKey,
+				PendingDeployKey: repo.PendingDeployKey,
+			})
+
+			if err != nil {
+				JSONError(rw, errorResp{Message: err.Error()}, http.StatusInternalServerError)
+				return
+			}
+
+			io.WriteString(rw, string(respBytes))
+		default:
+			JSONError(rw, errorResp{Message: "Something went wrong..."}, http.StatusInternalServerError)
+		}
+	}
+}
+
+func (s *Server) repoTreeHandler(repoService *repoPkg.Service) http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "GET":
+			conn, err := s.dialRepoServer()
+
+			if err != nil {
+				JSONError(rw, errorResp{Message: err.Error()}, http.StatusInternalServerError)
+				return
+			}
+
+			defer conn.Close()
+
+			rp := reposerver.NewRepoServiceClient(conn)
+			vars := mux.Vars(r)
+			idAsUInt, _ := strconv.ParseUint(vars["id"], 10, 32)
+			repo, err := repoService.Get(uint(idAsUInt))
+
+			if err != nil {
+				if err.Error() == "record not found" {
+					JSONError(rw, errorResp{Message: err.Error()}, http.StatusNotFound)
+				} else {
+					JSONError(rw, errorResp{Message: err.Error()}, http.StatusInternalServerError)
+				}
+				return
+			}
+
+			params := r.URL.Query()
+			message := reposerver.TreeRequest{RepoUrl: repo.Url, RepoId: strconv.FormatInt(int64(repo.ID), 10), Branch: repo.Branch, Path: params.Get("path"), Ref: params.Get("ref")}
+			tree, err := rp.ListTree(r.Context(), &message)
+
+			if err != nil {
+				JSONError(rw, errorResp{Message: status.Convert(err).Message()}, grpcHttpStatus(err))
+				return
+			}
+
+			respBytes, err := json.Marshal(tree)
+
+			if err != nil {
+				JSONError(rw, errorResp{Message: err.Error()}, http.StatusInternalServerError)
+				return
+			}
+
+			io.WriteString(rw, string(respBytes))
+		default:
+			JSONError(rw, errorResp{Message: "Something went wrong..."}, http.StatusInternalServerError)
+		}
+	}
+}
+
+func (s *Server) repoFileHandler(repoService *repoPkg.Service) http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "GET":
+			conn, err := s.dialRepoServer()
+
+			if err != nil {
+				JSONError(rw, errorResp{Message: err.Error()}, http.StatusInternalServerError)
+				return
+			}
+
+			defer conn.Close()
+
+			rp := reposerver.NewRepoServiceClient(conn)
+			vars := mux.Vars(r)
+			idAsUInt, _ := strconv.ParseUint(vars["id"], 10, 32)
+			repo, err := repoService.Get(uint(idAsUInt))
+
+			if err != nil {
+				if err.Error() == "record not found" {
+					JSONError(rw, errorResp{Message: err.Error()}, http.StatusNotFound)
+				} else {
+					JSONError(rw, errorResp{Message: err.Error()}, http.StatusInternalServerError)
+				}
+				return
+			}
+
+			params := r.URL.Query()
+			message := reposerver.FileRequest{RepoUrl: repo.Url, RepoId: strconv.FormatInt(int64(repo.ID), 10), Branch: repo.Branch, Path: params.Get("path"), Ref: params.Get("ref")}
+			file, err := rp.ReadFile(r.Context(), &message)
+
+			if err != nil {
+				JSONError(rw, errorResp{Message: status.Convert(err).Message()}, grpcHttpStatus(err))
+				return
+			}
+
+			respBytes, err := json.Marshal(FileHttpResponse{
+				Hash:      file.Hash,
+				Path:      file.Path,
+				Size:      file.Size,
+				Binary:    file.Binary,
+				Truncated: file.Truncated,
+				Content:   string(file.Content),
+			})
+
+			if err != nil {
+				JSONError(rw, errorResp{Message: err.Error()}, http.StatusInternalServerError)
+				return
+			}
+
+			io.WriteString(rw, string(respBytes))
+		default:
+			JSONError(rw, errorResp{Message: "Something went wrong..."}, http.StatusInternalServerError)
+		}
+	}
+}
+
+func (s *Server) repoApplicationsHandler(repoService *repoPkg.Service) http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		conn, err := s.dialRepoServer()
+
+		if err != nil {
+			JSONError(rw, errorResp{Message: err.Error()}, http.StatusInternalServerError)
+			return
+		}
+
+		defer conn.Close()
+
+		rp := reposerver.NewRepoServiceClient(conn)
+		vars := mux.Vars(r)
+		idAsUInt, _ := strconv.ParseUint(vars["id"], 10, 32)
+		repo, err := repoService.Get(uint(idAsUInt))
+
+		if err != nil {
+			if err.Error() == "record not found" {
+				JSONError(rw, errorResp{Message: err.Error()}, http.StatusNotFound)
+			} else {
+				JSONError(rw, errorResp{Message: err.Error()}, http.StatusInternalServerError)
+			}
+			return
+		}
+
+		switch r.Method {
+		case "GET":
+			resp, _, _, err := s.discoverApplications(r.Context(), rp, repo)
+
+			if err != nil {
+				JSONError(rw, errorResp{Message: status.Convert(err).Message()}, grpcHttpStatus(err))
+				return
+			}
+
+			respBytes, err := json.Marshal(resp)
+
+			if err != nil {
+				JSONError(rw, errorResp{Message: err.Error()}, http.StatusInternalServerError)
+				return
+			}
+
+			io.WriteString(rw, string(respBytes))
+		case "POST":
+			var discoverPayload application.DiscoverRequest
+			err := decodeJSONBody(rw, r, &discoverPayload)
+
+			if err != nil {
+				JSONError(rw, errorResp{Message: err.Error()}, http.StatusBadRequest)
+				return
+			}
+
+			// auto_discover is only changed when given, and prunes like a sync
+			// of an auto discovering repo does.
+			if discoverPayload.AutoDiscover != nil {
+				repo.AutoDiscover = *discoverPayload.AutoDiscover
+			}
+
+			resp, err := s.reconcileApplications(r.Context(), rp, repo, discoverPayload.Paths, repo.AutoDiscover)
+
+			if err != nil {
+				JSONError(rw, errorResp{Message: status.Convert(err).Message()}, grpcHttpStatus(err))
+				return
+			}
+
+			if repo.AutoDiscover {
+				repo.DiscoveryErrors = resp.Problems
+			}
+
+			repoService.Update(repo)
+
+			respBytes, err := json.Marshal(resp)
+
+			if err != nil {
+				JSONError(rw, errorResp{Message: err.Error()}, http.StatusInternalServerError)
+				return
+			}
+
+			io.WriteString(rw, string(respBytes))
+		default:
+			JSONError(rw, errorResp{Message: "Something went wrong..."}, http.StatusInternalServerError)
+		}
+	}
+}
+
+func (s *Server) gitHookHandler(repoService *repoPkg.Service) http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "POST":
+			vars := mux.Vars(r)
+			idAsUInt, err := strconv.ParseUint(vars["repoId"], 10, 32)
+
+			if err != nil {
+				JSONError(rw, errorResp{Message: err.Error()}, http.StatusBadRequest)
+				return
+			}
+
+			repo, err := repoService.Get(uint(idAsUInt))
+
+			if err != nil {
+				if err.Error() == "record not found" {
+					JSONError(rw, errorResp{Message: err.Error()}, http.StatusNotFound)
+				} else {
+					JSONError(rw, errorResp{Message: err.Error()}, http.StatusInternalServerError)
+				}
+				return
+			}
+
+			body, err := io.ReadAll(http.MaxBytesReader(rw, r.Body, maxWebhookBodySize))
+
+			if err != nil {
+				JSONError(rw, errorResp{Message: err.Error()}, http.StatusBadRequest)
+				return
+			}
+
+			var secret string
+
+			if len(repo.WebhookSecret) > 0 {
+				secret, err = s.keyring.decrypt(repo.WebhookSecret)
+
+				if err != nil {
+					JSONError(rw, errorResp{Message: err.Error()}, http.StatusInternalServerError)
+					return
+				}
 			}
 
-			err = repoService.Delete(repo)
+			push, err := webhook.Parse(r.Header, body, secret)
 
 			if err != nil {
-				JSONError(rw, errorResp{Message: err.Error()}, http.StatusInternalServerError)
+				switch err {
+				case webhook.ErrIgnoredEvent:
+					JSONError(rw, errorResp{Message: err.Error()}, http.StatusAccepted)
+				case webhook.ErrInvalidSignature:
+					JSONError(rw, errorResp{Message: err.Error()}, http.StatusUnauthorized)
+				default:
+					JSONError(rw, errorResp{Message: err.Error()}, http.StatusBadRequest)
+				}
 				return
 			}
 
-			message := reposerver.RemoveSshKeyRequest{RepoId: strconv.FormatInt(int64(repo.ID), 10)}
-			_, err = rp.RemoveSshKey(context.Background(), &message)
+			if !push.Matches(repo.Branch) {
+				JSONError(rw, errorResp{Message: fmt.Sprintf("push does not update branch %s", repo.Branch)}, http.StatusAccepted)
+				return
+			}
+
+			log.Infof("%s push to %s, syncing repo %d", push.Provider, repo.Branch, repo.ID)
+			syncId, started := s.startSync(repo)
+			resp := SyncHttpResponse{SyncId: syncId}
+
+			if !started {
+				s.syncs.requestRerun(repo.ID)
+				resp.Message = "sync already in progress, the push is synced once it finishes"
+			}
+
+			respBytes, err := json.Marshal(resp)
 
 			if err != nil {
 				JSONError(rw, errorResp{Message: err.Error()}, http.StatusInternalServerError)
 				return
 			}
 
-			http.Error(rw, "", http.StatusNoContent)
+			rw.WriteHeader(http.StatusAccepted)
+			io.WriteString(rw, string(respBytes))
 		default:
 			JSONError(rw, errorResp{Message: "Something went wrong..."}, http.StatusInternalServerError)
 		}
@@ -262,7 +766,7 @@ func (s *Server) repoHandler(repoService *repoPkg.Service) http.HandlerFunc {
 
 func (s *Server) applicationHandler(applicationService *application.Service, repoService *repoPkg.Service) http.HandlerFunc {
 	return func(rw http.ResponseWriter, r *http.Request) {
-		conn, err := grpc.Dial(s.ServerConfig.RepoServerAddress, grpc.WithTransportCredentials(insecure.NewCredentials()))
+		conn, err := s.dialRepoServer()
 
 		if err != nil {
 			log.Errorln(err)
@@ -300,22 +804,35 @@ func (s *Server) applicationHandler(applicationService *application.Service, rep
 			}
 
 			if err == nil {
-				repoDirRequest := reposerver.RepoDirRequest{RepoUrl: repo.Url}
-				repoDirResponse, err := rp.GetRepoDir(context.Background(), &repoDirRequest)
+				ref := r.URL.Query().Get("ref")
+				repoDirResponse, err := rp.GetRepoDir(r.Context(), repoDirRequest(repo, ref))
 
 				if err != nil {
 					log.Errorln(err)
+					resp.ManifestsError = status.Convert(err).Message()
 				}
 
-				fullManifestPath := path.Join(repoDirResponse.Path, app.ManifestPath)
-				message := reposerver.ManifestsRequest{Path: fullManifestPath}
-				response, err := rp.GetManifests(context.Background(), &message)
-
-				if err != nil {
-					log.Errorln(err)
+				if err == nil && ref != "" {
+					resp.Ref = ref
+					resp.Commit = repoDirResponse.Hash
 				}
 
-				resp.Manifests = response
+				if err == nil {
+					fullManifestPath := path.Join(repoDirResponse.Path, application.ManifestDir(app))
+					message := reposerver.ManifestsRequest{
+						Path:     fullManifestPath,
+						Overlay:  r.URL.Query().Get("overlay"),
+						Workload: application.WorkloadRef(app),
+					}
+					response, err := rp.GetManifests(context.Background(), &message)
+
+					if err != nil {
+						log.Errorln(err)
+						resp.ManifestsError = status.Convert(err).Message()
+					}
+
+					resp.Manifests = response
+				}
 			}
 
 			resp.App = app
@@ -339,7 +856,12 @@ func (s *Server) applicationHandler(applicationService *application.Service, rep
 				return
 			}
 
-			var updateAppPayload application.ApplicationUpdate
+			if rejectGitOwned(rw, app) {
+				return
+			}
+
+			// Settings left out of the payload keep their values.
+			updateAppPayload := application.ApplicationUpdate{AppSettings: app.AppSettings}
 			err = decodeJSONBody(rw, r, &updateAppPayload)
 
 			if err != nil {
@@ -347,10 +869,43 @@ func (s *Server) applicationHandler(applicationService *application.Service, rep
 				return
 			}
 
+			manifestType, err := application.ValidateManifestType(updateAppPayload.ManifestType, updateAppPayload.ChartPath)
+
+			if err != nil {
+				JSONError(rw, errorResp{Message: err.Error()}, http.StatusBadRequest)
+				return
+			}
+
+			if problems := application.ValidateSettings(updateAppPayload.AppSettings); len(problems) > 0 {
+				JSONError(rw, errorResp{Message: strings.Join(problems, "; ")}, http.StatusBadRequest)
+				return
+			}
+
+			workload, err := client.ResolveWorkload(updateAppPayload.Workload)
+
+			if err == nil {
+				err = s.clientset.CheckWorkloadAccess(r.Context(), workload)
+			}
+
+			if err != nil {
+				JSONError(rw, errorResp{Message: err.Error()}, http.StatusBadRequest)
+				return
+			}
+
+			moved := app.ManifestPath != updateAppPayload.ManifestPath || app.ChartPath != updateAppPayload.ChartPath || app.RepoID != updateAppPayload.RepoID
 			app.ManifestPath = updateAppPayload.ManifestPath
+			app.ManifestType = manifestType
+			app.ChartPath = updateAppPayload.ChartPath
+			app.Workload = workload
 			app.RepoID = updateAppPayload.RepoID
 			app.Name = updateAppPayload.Name
+			app.AppSettings = updateAppPayload.AppSettings
 			applicationService.Update(app)
+			s.watchWorkload(app.Workload)
+
+			if moved {
+				s.syncSparse(app.RepoID)
+			}
 
 			repo, err := repoService.Get(app.RepoID)
 
@@ -359,20 +914,19 @@ func (s *Server) applicationHandler(applicationService *application.Service, rep
 				return
 			}
 
-			repoDirRequest := reposerver.RepoDirRequest{RepoUrl: repo.Url}
-			repoDirResponse, err := rp.GetRepoDir(context.Background(), &repoDirRequest)
+			repoDirResponse, err := rp.GetRepoDir(context.Background(), repoDirRequest(repo, ""))
 
 			if err != nil {
 				JSONError(rw, errorResp{Message: err.Error()}, http.StatusInternalServerError)
 				return
 			}
 
-			fullManifestPath := path.Join(repoDirResponse.Path, app.ManifestPath)
-			message := reposerver.ManifestsRequest{Path: fullManifestPath}
+			fullManifestPath := path.Join(repoDirResponse.Path, application.ManifestDir(app))
+			message := reposerver.ManifestsRequest{Path: fullManifestPath, Workload: application.WorkloadRef(app)}
 			response, err := rp.GetManifests(context.Background(), &message)
 
 			if err != nil {
-				JSONError(rw, errorResp{Message: err.Error()}, http.StatusInternalServerError)
+				JSONError(rw, errorResp{Message: status.Convert(err).Message()}, grpcHttpStatus(err))
 				return
 			}
 
@@ -398,6 +952,10 @@ func (s *Server) applicationHandler(applicationService *application.Service, rep
 				return
 			}
 
+			if rejectGitOwned(rw, app) {
+				return
+			}
+
 			err = applicationService.Delete(app)
 
 			if err != nil {
@@ -412,6 +970,17 @@ func (s *Server) applicationHandler(applicationService *application.Service, rep
 	}
 }
 
+// rejectGitOwned answers 403 for changes to an application declared in the
+// config file of its repo, which would be undone by the next sync.
+func rejectGitOwned(rw http.ResponseWriter, app db.Application) bool {
+	if !app.GitOwned {
+		return false
+	}
+
+	JSONError(rw, errorResp{Message: fmt.Sprintf("application %s is managed by %s in its repo and is read-only here", app.Name, application.CONFIG_FILE)}, http.StatusForbidden)
+	return true
+}
+
 func (s *Server) applicationsHandler(service *application.Service) http.HandlerFunc {
 	return func(rw http.ResponseWriter, r *http.Request) {
 		switch r.Method {
@@ -434,7 +1003,37 @@ func (s *Server) applicationsHandler(service *application.Service) http.HandlerF
 				return
 			}
 
+			newAppPayload.ManifestType, err = application.ValidateManifestType(newAppPayload.ManifestType, newAppPayload.ChartPath)
+
+			if err != nil {
+				JSONError(rw, errorResp{Message: err.Error()}, http.StatusBadRequest)
+				return
+			}
+
+			if problems := application.ValidateSettings(newAppPayload.AppSettings); len(problems) > 0 {
+				JSONError(rw, errorResp{Message: strings.Join(problems, "; ")}, http.StatusBadRequest)
+				return
+			}
+
+			// Only the repo config file declares git owned applications and
+			// only discovery finds discovered ones.
+			newAppPayload.GitOwned = false
+			newAppPayload.Discovered = false
+
+			newAppPayload.Workload, err = client.ResolveWorkload(newAppPayload.Workload)
+
+			if err == nil {
+				err = s.clientset.CheckWorkloadAccess(r.Context(), newAppPayload.Workload)
+			}
+
+			if err != nil {
+				JSONError(rw, errorResp{Message: err.Error()}, http.StatusBadRequest)
+				return
+			}
+
 			newApp := service.Create(newAppPayload)
+			s.watchWorkload(newApp.Workload)
+			s.syncSparse(newApp.RepoID)
 			newAppBytes, err := json.Marshal(newApp)
 
 			if err != nil {
@@ -451,7 +1050,7 @@ func (s *Server) applicationsHandler(service *application.Service) http.HandlerF
 
 func (s *Server) settingsHandler() http.HandlerFunc {
 	return func(rw http.ResponseWriter, r *http.Request) {
-		conn, err := grpc.Dial(s.ServerConfig.RepoServerAddress, grpc.WithTransportCredentials(insecure.NewCredentials()))
+		conn, err := s.dialRepoServer()
 
 		if err != nil {
 			log.Errorln(err)
@@ -472,10 +1071,78 @@ func (s *Server) settingsHandler() http.HandlerFunc {
 			}
 
 			resp := SettingsHttpResponse{
-				RepoRoot:   paths.RepoRoot,
-				SshRoot:    paths.SshRoot,
-				PrivateKey: paths.PrivateKey,
+				RepoRoot:     paths.RepoRoot,
+				SshRoot:      paths.SshRoot,
+				PrivateKey:   paths.PrivateKey,
+				SecretsKeyId: s.keyring.current,
+			}
+			respBytes, err := json.Marshal(resp)
+
+			if err != nil {
+				JSONError(rw, errorResp{Message: err.Error()}, http.StatusInternalServerError)
+				return
+			}
+
+			io.WriteString(rw, string(respBytes))
+		default:
+			JSONError(rw, errorResp{Message: "Something went wrong..."}, http.StatusInternalServerError)
+		}
+	}
+}
+
+// reencryptHandler rewrites every application secret and repo webhook secret
+// under the current key of the keyring, so previous keys can be retired.
+// Values already under the current key are left as they are. Legacy values
+// are unauthenticated, a wrong SECRETS_KEY decrypts them to garbage, so they
+// are only rewritten with ?legacy=true, once SECRETS_KEY is known to be the
+// key they were written with, and counted otherwise.
+func (s *Server) reencryptHandler(secretService *secret.SecretService) http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "POST":
+			rewriteLegacy := false
+
+			if value := r.URL.Query().Get("legacy"); value != "" {
+				var err error
+				rewriteLegacy, err = strconv.ParseBool(value)
+
+				if err != nil {
+					JSONError(rw, errorResp{Message: fmt.Sprintf("invalid legacy %q", value)}, http.StatusBadRequest)
+					return
+				}
+			}
+
+			legacy := 0
+			reencrypt := func(value string) (string, bool, error) {
+				if !isLegacy(value) {
+					return s.reencrypt(value)
+				}
+
+				legacy++
+
+				if !rewriteLegacy {
+					return value, false, nil
+				}
+
+				return s.reencrypt(value)
+			}
+
+			secrets, webhookSecrets, err := secretService.Rewrite(reencrypt)
+
+			if err != nil {
+				JSONError(rw, errorResp{Message: err.Error()}, http.StatusInternalServerError)
+				return
+			}
+
+			resp := ReencryptHttpResponse{KeyId: s.keyring.current, Secrets: secrets, WebhookSecrets: webhookSecrets}
+
+			if rewriteLegacy {
+				resp.LegacyRewritten = legacy
+			} else {
+				resp.LegacySkipped = legacy
 			}
+
+			log.Infof("Re-encrypted %d secrets and %d webhook secrets with key %q, %d of them legacy values, %d legacy values skipped", secrets, webhookSecrets, s.keyring.current, resp.LegacyRewritten, resp.LegacySkipped)
 			respBytes, err := json.Marshal(resp)
 
 			if err != nil {
@@ -490,6 +1157,21 @@ func (s *Server) settingsHandler() http.HandlerFunc {
 	}
 }
 
+func (s *Server) reencrypt(value string) (string, bool, error) {
+	if s.keyring.isCurrent(value) {
+		return value, false, nil
+	}
+
+	plain, err := s.keyring.decrypt(value)
+
+	if err != nil {
+		return "", false, err
+	}
+
+	encrypted, err := s.keyring.encrypt(plain)
+	return encrypted, err == nil, err
+}
+
 func (s *Server) logsHandler() http.HandlerFunc {
 	return func(rw http.ResponseWriter, r *http.Request) {
 		switch r.Method {
@@ -606,7 +1288,7 @@ func (s *Server) applicationSecretsHandler(applicationService *application.Servi
 				return
 			}
 
-			encrypted, err := encrypt([]byte(s.SecretsKey), newSecretPayload.Value)
+			encrypted, err := s.keyring.encrypt(newSecretPayload.Value)
 
 			if err != nil {
 				JSONError(rw, errorResp{Message: err.Error()}, http.StatusInternalServerError)
@@ -690,7 +1372,7 @@ func (s *Server) applicationSecretHandler(applicationService *application.Servic
 				return
 			}
 
-			encrypted, err := encrypt([]byte(s.SecretsKey), updateSecretPayload.Value)
+			encrypted, err := s.keyring.encrypt(updateSecretPayload.Value)
 
 			if err != nil {
 				JSONError(rw, errorResp{Message: err.Error()}, http.StatusInternalServerError)
@@ -765,89 +1447,35 @@ func (s *Server) applicationJobHandler(applicationService *application.Service,
 
 			io.WriteString(rw, string(respBytes))
 		case "POST":
-			var jobPayload client.JobConfig
-			decoder := json.NewDecoder(r.Body)
-			err := decoder.Decode(&jobPayload)
-
-			if err != nil {
-				JSONError(rw, errorResp{Message: err.Error()}, http.StatusInternalServerError)
-				return
-			}
-
-			secretsMap := make(map[string]string)
-			secrets := secretService.GetAllByAppId(appIdUint)
+			app, err := applicationService.Get(appIdUint)
 
 			if err != nil {
-				JSONError(rw, errorResp{Message: err.Error()}, http.StatusInternalServerError)
-				return
-			}
-
-			for _, sc := range secrets {
-				decrypted, err := decrypt([]byte(s.SecretsKey), sc.Value)
-
-				if err != nil {
-					JSONError(rw, errorResp{Message: err.Error()}, http.StatusInternalServerError)
-					return
-				}
-
-				secretsMap[sc.Name] = decrypted
-			}
-
-			sp, _ := json.Marshal(jobPayload.Spec)
-			spAsString := string(sp)
-			reg := regexp.MustCompile(`{{([^}}]*)}}`)
-			matches := reg.FindAllStringSubmatch(spAsString, -1)
-
-			for _, v := range matches {
-				matchValue := strings.ReplaceAll(v[1], "secrets.", "")
-				keyVal, ok := secretsMap[matchValue]
-
-				if ok {
-					spAsString = strings.Replace(spAsString, v[0], keyVal, -1)
+				if err.Error() == "record not found" {
+					JSONError(rw, errorResp{Message: err.Error()}, http.StatusNotFound)
 				} else {
-					spAsString = strings.Replace(spAsString, v[0], "", -1)
+					JSONError(rw, errorResp{Message: err.Error()}, http.StatusInternalServerError)
 				}
+				return
 			}
 
-			json.Unmarshal([]byte(spAsString), &jobPayload.Spec)
-
-			jobName := fmt.Sprintf("%s-%s", jobPayload.ObjectMeta.Name, generateRandomString(12, charset))
-			jobConfig := client.JobConfig{ObjectMeta: jobPayload.ObjectMeta, Spec: jobPayload.Spec}
-			newJob := jobService.Create(job.Job{Name: jobName, ApplicationID: appIdUint})
-			jobId := strconv.FormatUint(uint64(newJob.ID), 10)
-			labels := make(map[string]string)
-
-			labels["invoked"] = ""
-			labels["job_id"] = jobId
-
-			jobRootLogsPath := filepath.Join(s.LogsPath, jobId)
-			err = os.RemoveAll(jobRootLogsPath)
+			var input map[string]interface{}
+			err = json.NewDecoder(r.Body).Decode(&input)
 
 			if err != nil {
-				log.Errorln(err)
+				JSONError(rw, errorResp{Message: err.Error()}, http.StatusBadRequest)
+				return
 			}
 
-			jobConfig.Labels = labels
-
-			spec, _ := json.Marshal(jobPayload.Spec)
-			meta, _ := json.Marshal(jobPayload.ObjectMeta)
-			newJob.Spec = spec
-			newJob.Meta = meta
-			jobService.Update(newJob)
-
-			resp, err := s.clientset.Run(jobName, jobConfig)
+			// A ref runs the job off another branch, tag or commit than the
+			// repo branch, pinned to its commit for the whole run.
+			runResp, err := s.runJob(r.Context(), jobService, secretService, app, r.URL.Query().Get("ref"), r.URL.Query().Get("overlay"), input)
 
 			if err != nil {
-				JSONError(rw, errorResp{Message: err.Error()}, http.StatusInternalServerError)
+				runErrorResponse(rw, err)
 				return
 			}
 
-			respBytes, err := json.Marshal(JobRunResponse{
-				Job:    newJob,
-				Config: jobConfig,
-				Spec:   resp.Spec,
-				Status: resp.Status,
-			})
+			respBytes, err := json.Marshal(runResp)
 
 			if err != nil {
 				JSONError(rw, errorResp{Message: err.Error()}, http.StatusInternalServerError)
@@ -941,7 +1569,7 @@ func (s *Server) wsHandler(hub *Hub) http.HandlerFunc {
 				return
 			}
 
-			client := &Client{Id: id, hub: hub, conn: conn, send: make(chan []byte, 256)}
+			client := &Client{Id: id, hub: hub, syncs: s.syncs, conn: conn, send: make(chan []byte, 256)}
 			client.hub.register <- client
 
 			go client.writePump()
@@ -1030,3 +1658,144 @@ func (s *Server) selfHandler() http.HandlerFunc {
 		}
 	}
 }
+
+func (s *Server) applicationLintHandler(applicationService *application.Service, repoService *repoPkg.Service) http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "GET":
+			conn, err := s.dialRepoServer()
+
+			if err != nil {
+				JSONError(rw, errorResp{Message: err.Error()}, http.StatusInternalServerError)
+				return
+			}
+
+			defer conn.Close()
+
+			rp := reposerver.NewRepoServiceClient(conn)
+			vars := mux.Vars(r)
+			idAsUInt, err := strconv.ParseUint(vars["id"], 10, 32)
+
+			if err != nil {
+				JSONError(rw, errorResp{Message: err.Error()}, http.StatusBadRequest)
+				return
+			}
+
+			app, err := applicationService.Get(uint(idAsUInt))
+
+			if err != nil {
+				if err.Error() == "record not found" {
+					JSONError(rw, errorResp{Message: err.Error()}, http.StatusNotFound)
+				} else {
+					JSONError(rw, errorResp{Message: err.Error()}, http.StatusInternalServerError)
+				}
+				return
+			}
+
+			repo, err := repoService.Get(app.RepoID)
+
+			if err != nil {
+				JSONError(rw, errorResp{Message: err.Error()}, http.StatusInternalServerError)
+				return
+			}
+
+			repoDirResponse, err := rp.GetRepoDir(r.Context(), repoDirRequest(repo, r.URL.Query().Get("ref")))
+
+			if err != nil {
+				JSONError(rw, errorResp{Message: status.Convert(err).Message()}, grpcHttpStatus(err))
+				return
+			}
+
+			message := reposerver.ManifestsRequest{
+				Path:     path.Join(repoDirResponse.Path, application.ManifestDir(app)),
+				Workload: application.WorkloadRef(app),
+			}
+			response, err := rp.ValidateManifests(r.Context(), &message)
+
+			if err != nil {
+				JSONError(rw, errorResp{Message: status.Convert(err).Message()}, grpcHttpStatus(err))
+				return
+			}
+
+			respBytes, err := json.Marshal(LintHttpResponse{Valid: response.Valid, Issues: response.Issues})
+
+			if err != nil {
+				JSONError(rw, errorResp{Message: err.Error()}, http.StatusInternalServerError)
+				return
+			}
+
+			io.WriteString(rw, string(respBytes))
+		default:
+			JSONError(rw, errorResp{Message: "Something went wrong..."}, http.StatusInternalServerError)
+		}
+	}
+}
+
+// applicationSecretsPreflightHandler renders a run like applicationJobHandler,
+// with the same body, ref and overlay, and reports the secrets it references
+// and the ones missing for it to start, without running it.
+func (s *Server) applicationSecretsPreflightHandler(applicationService *application.Service, secretService *secret.SecretService) http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "POST":
+			vars := mux.Vars(r)
+			appId, err := strconv.ParseUint(vars["id"], 10, 32)
+
+			if err != nil {
+				JSONError(rw, errorResp{Message: err.Error()}, http.StatusBadRequest)
+				return
+			}
+
+			app, err := applicationService.Get(uint(appId))
+
+			if err != nil {
+				if err.Error() == "record not found" {
+					JSONError(rw, errorResp{Message: err.Error()}, http.StatusNotFound)
+				} else {
+					JSONError(rw, errorResp{Message: err.Error()}, http.StatusInternalServerError)
+				}
+				return
+			}
+
+			// Values are not needed, the names tell which secrets are set.
+			set := make(map[string]string)
+			resp := SecretsPreflightHttpResponse{Referenced: []string{}, Set: []string{}, Missing: []string{}}
+
+			for _, sc := range secretService.GetAllByAppId(uint(appId)) {
+				set[sc.Name] = ""
+				resp.Set = append(resp.Set, sc.Name)
+			}
+
+			sort.Strings(resp.Set)
+
+			var input map[string]interface{}
+			err = json.NewDecoder(r.Body).Decode(&input)
+
+			if err != nil {
+				JSONError(rw, errorResp{Message: err.Error()}, http.StatusBadRequest)
+				return
+			}
+
+			rendered, _, err := s.renderRun(r.Context(), app, r.URL.Query().Get("ref"), r.URL.Query().Get("overlay"), input, set)
+
+			if err != nil {
+				runErrorResponse(rw, err)
+				return
+			}
+
+			referenced, missing := unresolvedSecrets(rendered, app.RequiredSecrets, set)
+			resp.Referenced = append(resp.Referenced, referenced...)
+			resp.Missing = append(resp.Missing, missing...)
+			respBytes, err := json.Marshal(resp)
+
+			if err != nil {
+				JSONError(rw, errorResp{Message: err.Error()}, http.StatusInternalServerError)
+				return
+			}
+
+			io.WriteString(rw, string(respBytes))
+		default:
+			JSONError(rw, errorResp{Message: "Something went wrong..."}, http.StatusInternalServerError)
+		}
+	}
+}
@@ -9,6 +9,9 @@ type RoomMessage struct {
 
 type JoinRoom struct {
 	client *Client
+	// replay is sent to the client as it joins, from the hub goroutine which
+	// owns its send channel, so it never reaches a closed one.
+	replay []Message
 }
 
 type Hub struct {
@@ -61,6 +64,14 @@ func (h *Hub) run() {
 				h.rooms[joinRoom.client.Id] = connections
 			}
 			h.rooms[joinRoom.client.Id][joinRoom.client] = true
+			if _, ok := h.clients[joinRoom.client]; ok {
+				for _, m := range joinRoom.replay {
+					select {
+					case joinRoom.client.send <- m:
+					default:
+					}
+				}
+			}
 		case leaveRoom := <-h.leaveRoom:
 			connections := h.rooms[leaveRoom.client.Id]
 			if connections != nil {
@@ -0,0 +1,103 @@
+package server
+
+import (
+	"context"
+	"path"
+	"time"
+
+	"github.com/kubefill/kubefill/pkg/application"
+	"github.com/kubefill/kubefill/pkg/db"
+	"github.com/kubefill/kubefill/pkg/job"
+	"github.com/kubefill/kubefill/pkg/secret"
+	"github.com/kubefill/kubefill/reposerver"
+	"github.com/robfig/cron/v3"
+	log "github.com/sirupsen/logrus"
+)
+
+// scheduledRunTimeout bounds rendering and starting a single scheduled run.
+const scheduledRunTimeout = time.Minute
+
+// runSchedules starts the jobs of the application schedules at the start of
+// every minute they are due, off the repo branch with the default data of
+// the application manifests.
+func (s *Server) runSchedules(jobService *job.JobService, secretService *secret.SecretService) {
+	for {
+		next := time.Now().Truncate(time.Minute).Add(time.Minute)
+		time.Sleep(time.Until(next))
+
+		for _, app := range s.applicationService.List() {
+			if scheduleDue(app.Schedules, next) {
+				go s.runScheduled(jobService, secretService, uint(app.Id))
+			}
+		}
+	}
+}
+
+// scheduleDue reports whether one of the schedules fires at minute.
+// Schedules which do not parse were reported when the application was saved.
+func scheduleDue(schedules []string, minute time.Time) bool {
+	for _, schedule := range schedules {
+		sched, err := cron.ParseStandard(schedule)
+
+		if err != nil {
+			continue
+		}
+
+		if sched.Next(minute.Add(-time.Second)).Equal(minute) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s *Server) runScheduled(jobService *job.JobService, secretService *secret.SecretService, appId uint) {
+	ctx, cancel := context.WithTimeout(context.Background(), scheduledRunTimeout)
+	defer cancel()
+
+	app, err := s.applicationService.Get(appId)
+
+	if err != nil {
+		log.Errorf("Scheduled run of application %d: %v", appId, err)
+		return
+	}
+
+	input, overlay, err := s.defaultInput(ctx, app)
+
+	if err != nil {
+		log.Errorf("Scheduled run of application %s: %v", app.Name, err)
+		return
+	}
+
+	runResp, err := s.runJob(ctx, jobService, secretService, app, "", overlay, input)
+
+	if err != nil {
+		log.Errorf("Scheduled run of application %s: %v", app.Name, err)
+		return
+	}
+
+	log.Infof("Scheduled run of application %s started job %s", app.Name, runResp.Job.Name)
+}
+
+// defaultInput returns the data the run form of the application starts
+// with, and the overlay of kustomize applications.
+func (s *Server) defaultInput(ctx context.Context, app db.Application) (map[string]interface{}, string, error) {
+	var input map[string]interface{}
+	var overlay string
+	err := s.withRepoServer(ctx, app, "", func(rp reposerver.RepoServiceClient, repoDir string) error {
+		manifests, err := rp.GetManifests(ctx, &reposerver.ManifestsRequest{
+			Path:     path.Join(repoDir, application.ManifestDir(app)),
+			Workload: application.WorkloadRef(app),
+		})
+
+		if err != nil {
+			return err
+		}
+
+		input = manifests.Data.AsMap()
+		overlay = manifests.Overlay
+		return nil
+	})
+
+	return input, overlay, err
+}
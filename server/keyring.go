@@ -0,0 +1,218 @@
+package server
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	cryptoRand "crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// DEFAULT_KEY_ID is the key ID of SECRETS_KEY in the keyring.
+const DEFAULT_KEY_ID = "default"
+
+var keyIdPattern = regexp.MustCompile(`^[A-Za-z0-9_.-]+$`)
+
+// keyring encrypts secrets with AES-GCM under its current key and decrypts
+// them with the key named by their key ID prefix, so a new key can be
+// introduced while values of the previous ones are still stored. Values
+// written before key IDs, with AES-CFB, carry no prefix and are decrypted
+// with the legacy key until they are re-encrypted.
+type keyring struct {
+	current string
+	keys    map[string]cipher.AEAD
+	legacy  []byte
+}
+
+// newKeyring builds the keyring of SECRETS_KEY, under DEFAULT_KEY_ID, and
+// of keys, comma separated id=key pairs. currentId names the key new values
+// are encrypted with, by default SECRETS_KEY or else the first of keys.
+func newKeyring(legacyKey string, keys string, currentId string) (*keyring, error) {
+	k := keyring{keys: make(map[string]cipher.AEAD), legacy: []byte(legacyKey)}
+	var ids []string
+
+	if legacyKey != "" {
+		aead, err := keyCipher(legacyKey)
+
+		if err != nil {
+			return nil, err
+		}
+
+		ids = append(ids, DEFAULT_KEY_ID)
+		k.keys[DEFAULT_KEY_ID] = aead
+	}
+
+	for _, entry := range strings.Split(keys, ",") {
+		entry = strings.TrimSpace(entry)
+
+		if entry == "" {
+			continue
+		}
+
+		id, key, ok := strings.Cut(entry, "=")
+
+		if !ok || key == "" {
+			return nil, fmt.Errorf("secrets key %q is not an id=key pair", id)
+		}
+
+		if !keyIdPattern.MatchString(id) {
+			return nil, fmt.Errorf("invalid secrets key id %q, use letters, digits, '.', '_' and '-'", id)
+		}
+
+		if _, ok := k.keys[id]; ok {
+			return nil, fmt.Errorf("secrets key id %q is used twice", id)
+		}
+
+		aead, err := keyCipher(key)
+
+		if err != nil {
+			return nil, err
+		}
+
+		ids = append(ids, id)
+		k.keys[id] = aead
+	}
+
+	k.current = currentId
+
+	if k.current == "" && len(ids) > 0 {
+		k.current = ids[0]
+	}
+
+	if _, ok := k.keys[k.current]; !ok && k.current != "" {
+		return nil, fmt.Errorf("current secrets key %q is not in the keyring", k.current)
+	}
+
+	return &k, nil
+}
+
+// keyCipher derives an AES-256 key from a secret of any length.
+func keyCipher(secret string) (cipher.AEAD, error) {
+	key := sha256.Sum256([]byte(secret))
+	block, err := aes.NewCipher(key[:])
+
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
+
+// ids returns the key IDs of the keyring, sorted.
+func (k *keyring) ids() []string {
+	var ids []string
+
+	for id := range k.keys {
+		ids = append(ids, id)
+	}
+
+	sort.Strings(ids)
+	return ids
+}
+
+// encrypt seals message under the current key, prefixed with its key ID,
+// which is also authenticated.
+func (k *keyring) encrypt(message string) (string, error) {
+	if k.current == "" {
+		return "", errors.New("no secrets key configured, set SECRETS_KEY or SECRETS_KEYS")
+	}
+
+	aead := k.keys[k.current]
+	nonce := make([]byte, aead.NonceSize())
+
+	if _, err := cryptoRand.Read(nonce); err != nil {
+		return "", fmt.Errorf("could not encrypt: %v", err)
+	}
+
+	sealed := aead.Seal(nonce, nonce, []byte(message), []byte(k.current))
+	return k.current + ":" + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// decrypt opens a value of any key of the keyring, or a legacy value.
+// Tampered values and values of unknown keys fail.
+func (k *keyring) decrypt(message string) (string, error) {
+	if isLegacy(message) {
+		return k.decryptLegacy(message)
+	}
+
+	id, encoded, _ := strings.Cut(message, ":")
+
+	aead, known := k.keys[id]
+
+	if !known {
+		return "", fmt.Errorf("secret encrypted with unknown key %q", id)
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(encoded)
+
+	if err != nil {
+		return "", fmt.Errorf("could not base64 decode: %v", err)
+	}
+
+	if len(sealed) < aead.NonceSize() {
+		return "", errors.New("secret is truncated")
+	}
+
+	plain, err := aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], []byte(id))
+
+	if err != nil {
+		return "", fmt.Errorf("secret of key %q does not decrypt, it was altered or the key changed", id)
+	}
+
+	return string(plain), nil
+}
+
+// isLegacy reports whether a value was written before key IDs.
+func isLegacy(message string) bool {
+	// base64 has no colon, legacy values never have a key ID.
+	return !strings.Contains(message, ":")
+}
+
+// isCurrent reports whether a value is encrypted with the current key.
+func (k *keyring) isCurrent(message string) bool {
+	return k.current != "" && strings.HasPrefix(message, k.current+":")
+}
+
+// decryptLegacy decrypts an AES-CFB value written before key IDs, with
+// SECRETS_KEY used as the AES key as it is. CFB is not authenticated, a
+// wrong key returns garbage rather than an error, which is caught when it is
+// not even text. Values were set as JSON strings, so they always are.
+func (k *keyring) decryptLegacy(message string) (string, error) {
+	cipherText, err := base64.StdEncoding.DecodeString(message)
+
+	if err != nil {
+		return "", fmt.Errorf("could not base64 decode: %v", err)
+	}
+
+	if len(k.legacy) == 0 {
+		return "", errors.New("secret was encrypted with SECRETS_KEY, which is not set")
+	}
+
+	block, err := aes.NewCipher(k.legacy)
+
+	if err != nil {
+		return "", fmt.Errorf("could not create new cipher: %v", err)
+	}
+
+	if len(cipherText) < aes.BlockSize {
+		return "", fmt.Errorf("invalid ciphertext block size")
+	}
+
+	iv := cipherText[:aes.BlockSize]
+	cipherText = cipherText[aes.BlockSize:]
+
+	stream := cipher.NewCFBDecrypter(block, iv)
+	stream.XORKeyStream(cipherText, cipherText)
+
+	if !utf8.Valid(cipherText) {
+		return "", errors.New("secret does not decrypt to text, SECRETS_KEY is not the key it was encrypted with")
+	}
+
+	return string(cipherText), nil
+}
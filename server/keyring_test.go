@@ -0,0 +1,249 @@
+package server
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/base64"
+	"strings"
+	"testing"
+)
+
+const (
+	testLegacyKey = "0123456789abcdef0123456789abcdef"
+	testMessage   = `the quick brown fox jumps over the lazy dog, "quoted" and ünïcode`
+)
+
+// encryptLegacy writes a value the way secrets were stored before key IDs,
+// with a fixed IV so the tests are deterministic.
+func encryptLegacy(t *testing.T, key string, message string) string {
+	block, err := aes.NewCipher([]byte(key))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cipherText := make([]byte, aes.BlockSize+len(message))
+	stream := cipher.NewCFBEncrypter(block, cipherText[:aes.BlockSize])
+	stream.XORKeyStream(cipherText[aes.BlockSize:], []byte(message))
+	return base64.StdEncoding.EncodeToString(cipherText)
+}
+
+func TestNewKeyring(t *testing.T) {
+	tests := []struct {
+		name      string
+		legacyKey string
+		keys      string
+		currentId string
+		current   string
+		err       string
+	}{
+		{name: "legacy key only", legacyKey: testLegacyKey, current: DEFAULT_KEY_ID},
+		{name: "additional keys", keys: "a=one, b=two", current: "a"},
+		{name: "legacy key first", legacyKey: testLegacyKey, keys: "a=one", current: DEFAULT_KEY_ID},
+		{name: "current key id", legacyKey: testLegacyKey, keys: "a=one,b=two", currentId: "b", current: "b"},
+		{name: "no keys"},
+		{name: "not a pair", keys: "a", err: "not an id=key pair"},
+		{name: "empty key", keys: "a=", err: "not an id=key pair"},
+		{name: "invalid id", keys: "a:b=one", err: "invalid secrets key id"},
+		{name: "id used twice", keys: "a=one,a=two", err: "used twice"},
+		{name: "default id used twice", legacyKey: testLegacyKey, keys: "default=one", err: "used twice"},
+		{name: "unknown current key", keys: "a=one", currentId: "b", err: "not in the keyring"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			k, err := newKeyring(test.legacyKey, test.keys, test.currentId)
+
+			if test.err != "" {
+				if err == nil || !strings.Contains(err.Error(), test.err) {
+					t.Fatalf("expected an error containing %q, got %v", test.err, err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if k.current != test.current {
+				t.Fatalf("expected current key %q, got %q", test.current, k.current)
+			}
+		})
+	}
+}
+
+func TestKeyringEncryptDecrypt(t *testing.T) {
+	old, err := newKeyring("", "old=one", "")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rotated, err := newKeyring("", "old=one,new=two", "new")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	other, err := newKeyring("", "old=other", "")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	empty, err := newKeyring("", "", "")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	oldValue, err := old.encrypt(testMessage)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	newValue, err := rotated.encrypt(testMessage)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	id, encoded, _ := strings.Cut(oldValue, ":")
+	sealed, _ := base64.StdEncoding.DecodeString(encoded)
+	sealed[len(sealed)-1] ^= 1
+	tampered := id + ":" + base64.StdEncoding.EncodeToString(sealed)
+
+	tests := []struct {
+		name    string
+		keyring *keyring
+		value   string
+		current bool
+		err     string
+	}{
+		{name: "same key", keyring: old, value: oldValue, current: true},
+		{name: "previous key", keyring: rotated, value: oldValue, current: false},
+		{name: "current key", keyring: rotated, value: newValue, current: true},
+		{name: "unknown key", keyring: old, value: newValue, err: "unknown key"},
+		{name: "changed key", keyring: other, value: oldValue, current: true, err: "does not decrypt"},
+		{name: "tampered", keyring: old, value: tampered, current: true, err: "does not decrypt"},
+		{name: "other key id", keyring: rotated, value: "new:" + encoded, current: true, err: "does not decrypt"},
+		{name: "truncated", keyring: old, value: "old:AAAA", current: true, err: "truncated"},
+		{name: "not base64", keyring: old, value: "old:%%%", current: true, err: "base64"},
+		{name: "no keys", keyring: empty, value: oldValue, err: "unknown key"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if current := test.keyring.isCurrent(test.value); current != test.current {
+				t.Errorf("expected isCurrent %t, got %t", test.current, current)
+			}
+
+			decrypted, err := test.keyring.decrypt(test.value)
+
+			if test.err != "" {
+				if err == nil || !strings.Contains(err.Error(), test.err) {
+					t.Fatalf("expected an error containing %q, got %v", test.err, err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if decrypted != testMessage {
+				t.Fatalf("expected %q, got %q", testMessage, decrypted)
+			}
+		})
+	}
+
+	if _, err := empty.encrypt(testMessage); err == nil {
+		t.Error("expected encrypting without keys to fail")
+	}
+}
+
+func TestKeyringLegacy(t *testing.T) {
+	legacy := encryptLegacy(t, testLegacyKey, testMessage)
+	otherKey := strings.Repeat("x", len(testLegacyKey))
+
+	tests := []struct {
+		name      string
+		legacyKey string
+		value     string
+		err       string
+	}{
+		{name: "legacy key", legacyKey: testLegacyKey, value: legacy},
+		{name: "wrong legacy key", legacyKey: otherKey, value: legacy, err: "not the key it was encrypted with"},
+		{name: "legacy key not set", value: legacy, err: "not set"},
+		{name: "short", legacyKey: testLegacyKey, value: base64.StdEncoding.EncodeToString([]byte("short")), err: "block size"},
+		{name: "not base64", legacyKey: testLegacyKey, value: "%%%", err: "base64"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if !isLegacy(test.value) {
+				t.Fatalf("expected %q to be legacy", test.value)
+			}
+
+			k, err := newKeyring(test.legacyKey, "a=one", "a")
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if k.isCurrent(test.value) {
+				t.Error("expected a legacy value never to be current")
+			}
+
+			decrypted, err := k.decrypt(test.value)
+
+			if test.err != "" {
+				if err == nil || !strings.Contains(err.Error(), test.err) {
+					t.Fatalf("expected an error containing %q, got %v", test.err, err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if decrypted != testMessage {
+				t.Fatalf("expected %q, got %q", testMessage, decrypted)
+			}
+		})
+	}
+}
+
+func TestIsLegacy(t *testing.T) {
+	k, err := newKeyring(testLegacyKey, "", "")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	value, err := k.encrypt(testMessage)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		value  string
+		legacy bool
+	}{
+		{value: value, legacy: false},
+		{value: encryptLegacy(t, testLegacyKey, testMessage), legacy: true},
+		{value: "default:AAAA", legacy: false},
+	}
+
+	for _, test := range tests {
+		if legacy := isLegacy(test.value); legacy != test.legacy {
+			t.Errorf("isLegacy(%q) = %t, expected %t", test.value, legacy, test.legacy)
+		}
+	}
+}
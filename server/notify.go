@@ -0,0 +1,132 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/smtp"
+	"strings"
+	"time"
+
+	"github.com/kubefill/kubefill/pkg/application"
+	"github.com/kubefill/kubefill/pkg/client"
+	"github.com/kubefill/kubefill/pkg/db"
+	log "github.com/sirupsen/logrus"
+)
+
+// JobNotification is the body POSTed to webhook notification targets.
+type JobNotification struct {
+	JobId         uint   `json:"job_id"`
+	Job           string `json:"job"`
+	ApplicationId uint   `json:"application_id"`
+	Application   string `json:"application"`
+	Phase         string `json:"phase"`
+	PreviousPhase string `json:"previous_phase"`
+}
+
+var notifyClient = &http.Client{Timeout: 10 * time.Second}
+
+// notifyPhase tells the notification targets of the job's application
+// about its new phase. Targets are sent to in the background, so a slow one
+// never holds up the informer.
+func (s *Server) notifyPhase(job db.Job, previous string) {
+	app, err := s.applicationService.Get(job.ApplicationID)
+
+	if err != nil {
+		log.Errorf("Failed to notify about job %d: %v", job.ID, err)
+		return
+	}
+
+	notification := JobNotification{
+		JobId:         job.ID,
+		Job:           job.Name,
+		ApplicationId: app.ID,
+		Application:   app.Name,
+		Phase:         job.Phase,
+		PreviousPhase: previous,
+	}
+
+	for _, target := range app.Notifications {
+		if !notifiedOf(target, job.Phase) {
+			continue
+		}
+
+		go func(target db.NotificationTarget) {
+			if err := s.notify(target, notification); err != nil {
+				log.Errorf("Failed to notify %s %s about job %d: %v", target.Type, target.Target, job.ID, err)
+			}
+		}(target)
+	}
+}
+
+// notifiedOf reports whether target wants to hear about phase, any final
+// phase when it lists none.
+func notifiedOf(target db.NotificationTarget, phase string) bool {
+	if len(target.Phases) == 0 {
+		return phase == client.PHASE_SUCCEEDED || phase == client.PHASE_FAILED
+	}
+
+	for _, p := range target.Phases {
+		if p == phase {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s *Server) notify(target db.NotificationTarget, notification JobNotification) error {
+	text := fmt.Sprintf("Job %s of application %s is %s", notification.Job, notification.Application, notification.Phase)
+
+	switch target.Type {
+	case application.NOTIFY_WEBHOOK:
+		return postJSON(target.Target, notification)
+	case application.NOTIFY_SLACK:
+		return postJSON(target.Target, map[string]string{"text": text})
+	case application.NOTIFY_EMAIL:
+		return s.sendMail(target.Target, text)
+	}
+
+	return fmt.Errorf("unknown notification type %q", target.Type)
+}
+
+func postJSON(url string, body interface{}) error {
+	bodyBytes, err := json.Marshal(body)
+
+	if err != nil {
+		return err
+	}
+
+	resp, err := notifyClient.Post(url, "application/json", bytes.NewReader(bodyBytes))
+
+	if err != nil {
+		return err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("%s answered %s", url, resp.Status)
+	}
+
+	return nil
+}
+
+// sendMail sends text to the address through the configured SMTP server,
+// authenticating when a username is set.
+func (s *Server) sendMail(to string, text string) error {
+	if s.SMTPAddress == "" {
+		return fmt.Errorf("no SMTP server is configured")
+	}
+
+	var auth smtp.Auth
+
+	if s.SMTPUsername != "" {
+		host := strings.Split(s.SMTPAddress, ":")[0]
+		auth = smtp.PlainAuth("", s.SMTPUsername, s.SMTPPassword, host)
+	}
+
+	message := fmt.Sprintf("From: %s\r\nTo: %s\r\nSubject: %s\r\n\r\n%s\r\n", s.SMTPFrom, to, text, text)
+	return smtp.SendMail(s.SMTPAddress, auth, s.SMTPFrom, []string{to}, []byte(message))
+}
@@ -0,0 +1,131 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+	repoPkg "github.com/kubefill/kubefill/pkg/repo"
+	"github.com/kubefill/kubefill/reposerver"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// How long listing the refs of a remote may take.
+const listRefsTimeout = 30 * time.Second
+
+func (s *Server) repoRefsHandler(repoService *repoPkg.Service) http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "GET":
+			conn, err := s.dialRepoServer()
+
+			if err != nil {
+				JSONError(rw, errorResp{Message: err.Error()}, http.StatusInternalServerError)
+				return
+			}
+
+			defer conn.Close()
+
+			rp := reposerver.NewRepoServiceClient(conn)
+			vars := mux.Vars(r)
+			idAsUInt, _ := strconv.ParseUint(vars["id"], 10, 32)
+			repo, err := repoService.Get(uint(idAsUInt))
+
+			if err != nil {
+				if err.Error() == "record not found" {
+					JSONError(rw, errorResp{Message: err.Error()}, http.StatusNotFound)
+				} else {
+					JSONError(rw, errorResp{Message: err.Error()}, http.StatusInternalServerError)
+				}
+				return
+			}
+
+			ctx, cancel := context.WithTimeout(r.Context(), listRefsTimeout)
+			defer cancel()
+
+			refs, err := rp.ListRefs(ctx, &reposerver.ListRefsRequest{RepoUrl: repo.Url, RepoId: strconv.FormatInt(int64(repo.ID), 10)})
+
+			if err != nil {
+				JSONError(rw, errorResp{Message: status.Convert(err).Message()}, grpcHttpStatus(err))
+				return
+			}
+
+			resp := RefsHttpResponse{DefaultBranch: refs.DefaultBranch, Branches: []RefHttpResponse{}, Tags: []RefHttpResponse{}}
+
+			for _, ref := range refs.Branches {
+				resp.Branches = append(resp.Branches, RefHttpResponse{Name: ref.Name, Hash: ref.Hash})
+			}
+
+			for _, ref := range refs.Tags {
+				resp.Tags = append(resp.Tags, RefHttpResponse{Name: ref.Name, Hash: ref.Hash})
+			}
+
+			respBytes, err := json.Marshal(resp)
+
+			if err != nil {
+				JSONError(rw, errorResp{Message: err.Error()}, http.StatusInternalServerError)
+				return
+			}
+
+			io.WriteString(rw, string(respBytes))
+		default:
+			JSONError(rw, errorResp{Message: "Something went wrong..."}, http.StatusInternalServerError)
+		}
+	}
+}
+
+// resolveBranch checks that branch exists on the remote, an empty branch
+// resolves to the default branch of the remote. The remote is listed with
+// sshKey, or else with the saved key of repoId. An SSH remote without a key
+// yet, which waits for a deploy key, cannot be listed, then the branch is
+// only checked to be a valid branch name. Problems with the remote are
+// InvalidArgument errors, it is the URL of the request which does not work.
+func resolveBranch(ctx context.Context, rp reposerver.RepoServiceClient, url string, repoId string, sshKey string, branch string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, listRefsTimeout)
+	defer cancel()
+
+	refs, err := rp.ListRefs(ctx, &reposerver.ListRefsRequest{RepoUrl: url, RepoId: repoId, SshKey: sshKey})
+
+	if status.Code(err) == codes.FailedPrecondition {
+		if branch == "" {
+			return "", status.Errorf(codes.InvalidArgument, "branch is required, %s", status.Convert(err).Message())
+		}
+
+		if err := reposerver.ValidateBranch(branch); err != nil {
+			return "", status.Error(codes.InvalidArgument, err.Error())
+		}
+
+		log.Infof("Not validating branch %s of %s: %s", branch, url, status.Convert(err).Message())
+		return branch, nil
+	}
+
+	if status.Code(err) == codes.Unavailable {
+		return "", status.Error(codes.InvalidArgument, status.Convert(err).Message())
+	}
+
+	if err != nil {
+		return "", err
+	}
+
+	if branch == "" {
+		if refs.DefaultBranch == "" {
+			return "", status.Errorf(codes.InvalidArgument, "branch is required, %s has no default branch", url)
+		}
+
+		return refs.DefaultBranch, nil
+	}
+
+	for _, ref := range refs.Branches {
+		if ref.Name == branch {
+			return branch, nil
+		}
+	}
+
+	return "", status.Errorf(codes.InvalidArgument, "branch %s not found in %s", branch, url)
+}
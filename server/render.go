@@ -0,0 +1,261 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"path"
+
+	"github.com/kubefill/kubefill/pkg/application"
+	"github.com/kubefill/kubefill/pkg/client"
+	"github.com/kubefill/kubefill/pkg/db"
+	manifestPkg "github.com/kubefill/kubefill/pkg/manifest"
+	"github.com/kubefill/kubefill/pkg/tmpl"
+	"github.com/kubefill/kubefill/reposerver"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	structpb "google.golang.org/protobuf/types/known/structpb"
+)
+
+type repoFunc func(rp reposerver.RepoServiceClient, repoDir string) error
+
+// withRepoServer connects to the reposerver and calls fn with the checkout
+// directory of the application's repo, of ref when it is not empty.
+func (s *Server) withRepoServer(ctx context.Context, app db.Application, ref string, fn repoFunc) error {
+	conn, err := s.dialRepoServer()
+
+	if err != nil {
+		return err
+	}
+
+	defer conn.Close()
+
+	rp := reposerver.NewRepoServiceClient(conn)
+	checkout, err := s.repoCheckout(ctx, rp, app, ref)
+
+	if err != nil {
+		return err
+	}
+
+	return fn(rp, checkout.Path)
+}
+
+// repoCheckout returns the checkout of the application's repo branch, or of
+// ref when it is not empty, which the reposerver fetches on first use.
+func (s *Server) repoCheckout(ctx context.Context, rp reposerver.RepoServiceClient, app db.Application, ref string) (*reposerver.RepoDirResponse, error) {
+	repo, err := s.repoService.Get(app.RepoID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return rp.GetRepoDir(ctx, repoDirRequest(repo, ref))
+}
+
+// pinRef resolves a ref of the application's repo to its commit, so every
+// render of a run reads the same commit even when the ref moves meanwhile.
+func (s *Server) pinRef(ctx context.Context, app db.Application, ref string) (string, error) {
+	conn, err := s.dialRepoServer()
+
+	if err != nil {
+		return "", err
+	}
+
+	defer conn.Close()
+
+	checkout, err := s.repoCheckout(ctx, reposerver.NewRepoServiceClient(conn), app, ref)
+
+	if err != nil {
+		return "", err
+	}
+
+	return checkout.Hash, nil
+}
+
+// renderRun renders a run of the application with the secret references of
+// secrets. A ref is pinned to its commit first, which is returned and which
+// the rest of the run reads.
+func (s *Server) renderRun(ctx context.Context, app db.Application, ref string, overlay string, input map[string]interface{}, secrets map[string]string) (*manifestPkg.Manifest, string, error) {
+	var commit string
+
+	if ref != "" {
+		var err error
+		commit, err = s.pinRef(ctx, app, ref)
+
+		if err != nil {
+			return nil, "", err
+		}
+	}
+
+	// Templates render secret references rather than values, the values
+	// only reach the cluster in the Secret of the run.
+	rendered, err := s.renderManifest(ctx, app, commit, input, secretRefs(secrets), overlay, application.WorkloadRef(app))
+
+	if err != nil {
+		return nil, "", err
+	}
+
+	return rendered, commit, nil
+}
+
+// renderChart renders a helm application's chart with the submitted values.
+func (s *Server) renderChart(ctx context.Context, app db.Application, ref string, values map[string]interface{}, workload string) (*manifestPkg.Manifest, error) {
+	var rendered *manifestPkg.Manifest
+	err := s.withRepoServer(ctx, app, ref, func(rp reposerver.RepoServiceClient, repoDir string) error {
+		valuesStruct, err := structpb.NewStruct(values)
+
+		if err != nil {
+			return err
+		}
+
+		resp, err := rp.RenderChart(ctx, &reposerver.RenderRequest{
+			Path:        path.Join(repoDir, app.ChartPath),
+			Values:      valuesStruct,
+			ReleaseName: app.Name,
+			Workload:    workload,
+		})
+
+		if err != nil {
+			return err
+		}
+
+		rendered = renderedManifest(resp)
+		return nil
+	})
+
+	return rendered, err
+}
+
+// buildOverlay builds a kustomize application's overlay and patches the
+// submitted form values onto its workload.
+func (s *Server) buildOverlay(ctx context.Context, app db.Application, ref string, overlay string, patch map[string]interface{}, workload string) (*manifestPkg.Manifest, error) {
+	var rendered *manifestPkg.Manifest
+	err := s.withRepoServer(ctx, app, ref, func(rp reposerver.RepoServiceClient, repoDir string) error {
+		patchStruct, err := structpb.NewStruct(patch)
+
+		if err != nil {
+			return err
+		}
+
+		resp, err := rp.BuildOverlay(ctx, &reposerver.BuildRequest{
+			Path:     path.Join(repoDir, app.ManifestPath),
+			Overlay:  overlay,
+			Patch:    patchStruct,
+			Workload: workload,
+		})
+
+		if err != nil {
+			return err
+		}
+
+		rendered = renderedManifest(resp)
+		return nil
+	})
+
+	return rendered, err
+}
+
+// renderTemplate renders a template application's job template with the
+// form params and references to the application secrets.
+func (s *Server) renderTemplate(ctx context.Context, app db.Application, ref string, params map[string]interface{}, secrets map[string]string, workload string) (*manifestPkg.Manifest, error) {
+	var rendered *manifestPkg.Manifest
+	err := s.withRepoServer(ctx, app, ref, func(rp reposerver.RepoServiceClient, repoDir string) error {
+		manifests, err := rp.GetManifests(ctx, &reposerver.ManifestsRequest{Path: path.Join(repoDir, app.ManifestPath)})
+
+		if err != nil {
+			return err
+		}
+
+		if manifests.Template == "" {
+			return status.Errorf(codes.FailedPrecondition, "%s not found in %s", tmpl.TEMPLATE_FILE, app.ManifestPath)
+		}
+
+		// Secrets the template reads but which are not set render as their
+		// reference too, so they are reported rather than left empty.
+		refs := make(map[string]string, len(secrets))
+
+		for name, value := range secrets {
+			refs[name] = value
+		}
+
+		for _, name := range tmpl.SecretRefs(manifests.Template) {
+			if _, ok := refs[name]; !ok {
+				refs[name] = secretRef(name)
+			}
+		}
+
+		rendered, err = tmpl.Render(manifests.Template, params, refs, workload)
+		return err
+	})
+
+	return rendered, err
+}
+
+// manifestResources returns the objects a static application's data file
+// creates next to its workload.
+func (s *Server) manifestResources(ctx context.Context, app db.Application, ref string, workload string) ([]map[string]interface{}, error) {
+	var resources []map[string]interface{}
+	err := s.withRepoServer(ctx, app, ref, func(rp reposerver.RepoServiceClient, repoDir string) error {
+		manifests, err := rp.GetManifests(ctx, &reposerver.ManifestsRequest{
+			Path:     path.Join(repoDir, app.ManifestPath),
+			Workload: workload,
+		})
+
+		if err != nil {
+			return err
+		}
+
+		for _, resource := range manifests.Resources {
+			resources = append(resources, resource.AsMap())
+		}
+
+		return nil
+	})
+
+	return resources, err
+}
+
+func renderedManifest(resp *reposerver.RenderResponse) *manifestPkg.Manifest {
+	rendered := manifestPkg.Manifest{Job: resp.Job.AsMap()}
+
+	for _, resource := range resp.Resources {
+		rendered.Resources = append(rendered.Resources, resource.AsMap())
+	}
+
+	return &rendered
+}
+
+// renderManifest produces the workload and supporting objects of a run from
+// the request body, according to the application's manifest type. The
+// manifests are read from ref, or the repo branch when it is empty.
+func (s *Server) renderManifest(ctx context.Context, app db.Application, ref string, input map[string]interface{}, secrets map[string]string, overlay string, workload string) (*manifestPkg.Manifest, error) {
+	switch app.ManifestType {
+	case application.MANIFEST_TYPE_TEMPLATE:
+		return s.renderTemplate(ctx, app, ref, input, secrets, workload)
+	case application.MANIFEST_TYPE_HELM:
+		return s.renderChart(ctx, app, ref, input, workload)
+	case application.MANIFEST_TYPE_KUSTOMIZE:
+		return s.buildOverlay(ctx, app, ref, overlay, input, workload)
+	}
+
+	// Supporting objects always come from the repo, never the request.
+	resources, err := s.manifestResources(ctx, app, ref, workload)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &manifestPkg.Manifest{Job: input, Resources: resources}, nil
+}
+
+func toJobConfig(job map[string]interface{}, resources []map[string]interface{}) (client.JobConfig, error) {
+	var jobConfig client.JobConfig
+	jobBytes, err := json.Marshal(job)
+
+	if err != nil {
+		return jobConfig, err
+	}
+
+	err = json.Unmarshal(jobBytes, &jobConfig)
+	jobConfig.Resources = resources
+	return jobConfig, err
+}
@@ -0,0 +1,182 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strconv"
+
+	"github.com/kubefill/kubefill/pkg/application"
+	"github.com/kubefill/kubefill/pkg/client"
+	"github.com/kubefill/kubefill/pkg/db"
+	"github.com/kubefill/kubefill/reposerver"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// reconcileRepoConfig applies the application.CONFIG_FILE of the synced
+// commit. The applications it declares are created, or updated, taking over
+// discovered ones of the same name, and git owned applications it no longer
+// declares are deleted, as are all of them once the file is removed. A file
+// with problems, a clash with an application created by hand among them,
+// changes nothing, its problems are recorded on the repo instead.
+func (s *Server) reconcileRepoConfig(ctx context.Context, rp reposerver.RepoServiceClient, repo db.Repo) error {
+	config, problems, err := s.readRepoConfig(ctx, rp, repo)
+
+	if err != nil {
+		return err
+	}
+
+	apps, err := s.applicationService.ListByRepoId(repo.ID)
+
+	if err != nil {
+		return err
+	}
+
+	existing := make(map[string]db.Application)
+
+	for _, app := range apps {
+		existing[app.Name] = app
+	}
+
+	for i, appConfig := range config.Applications {
+		if app, ok := existing[appConfig.Name]; ok && !app.GitOwned && !app.Discovered {
+			problems = append(problems, fmt.Sprintf("applications[%d] (%s): application %d of the same name was created by hand, rename or delete one of them", i, appConfig.Name, app.ID))
+		}
+	}
+
+	if !reflect.DeepEqual(repo.ConfigErrors, problems) && (len(repo.ConfigErrors) > 0 || len(problems) > 0) {
+		repo.ConfigErrors = problems
+		err = s.repoService.Update(repo)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(problems) > 0 {
+		log.Warnf("repo %d: not applying %s: %v", repo.ID, application.CONFIG_FILE, problems)
+		return nil
+	}
+
+	declared := make(map[string]bool)
+	created, updated, removed := 0, 0, 0
+
+	for _, appConfig := range config.Applications {
+		declared[appConfig.Name] = true
+		app, ok := existing[appConfig.Name]
+
+		if !ok {
+			newApp := s.applicationService.Create(application.Application{
+				Name:         appConfig.Name,
+				RepoID:       repo.ID,
+				ManifestPath: normalizeManifestPath(appConfig.Path),
+				ManifestType: appConfig.Type,
+				ChartPath:    appConfig.ChartPath,
+				Workload:     appConfig.Workload,
+				GitOwned:     true,
+				AppSettings:  appConfig.Settings(),
+			})
+			s.watchWorkload(newApp.Workload)
+			created++
+			continue
+		}
+
+		wanted := app
+		wanted.ManifestPath = normalizeManifestPath(appConfig.Path)
+		wanted.ManifestType = appConfig.Type
+		wanted.ChartPath = appConfig.ChartPath
+		wanted.Workload = appConfig.Workload
+		wanted.GitOwned = true
+		wanted.Discovered = false
+		wanted.AppSettings = appConfig.Settings()
+
+		if reflect.DeepEqual(app, wanted) {
+			continue
+		}
+
+		if app.Discovered {
+			log.Infof("repo %d: %s takes over discovered application %d (%s)", repo.ID, application.CONFIG_FILE, app.ID, app.Name)
+		}
+
+		err = s.applicationService.Update(wanted)
+
+		if err != nil {
+			return err
+		}
+
+		s.watchWorkload(wanted.Workload)
+		updated++
+	}
+
+	for _, app := range apps {
+		if !app.GitOwned || declared[app.Name] {
+			continue
+		}
+
+		err = s.applicationService.Delete(app)
+
+		if err != nil {
+			return err
+		}
+
+		removed++
+	}
+
+	if created > 0 || updated > 0 {
+		s.syncSparse(repo.ID)
+	}
+
+	if created > 0 || updated > 0 || removed > 0 {
+		log.Infof("repo %d: %s created %d, updated %d and removed %d applications", repo.ID, application.CONFIG_FILE, created, updated, removed)
+	}
+
+	return nil
+}
+
+// readRepoConfig reads and validates the config file of the synced commit.
+// A repo without one declares no applications.
+func (s *Server) readRepoConfig(ctx context.Context, rp reposerver.RepoServiceClient, repo db.Repo) (application.RepoConfig, []string, error) {
+	file, err := rp.ReadFile(ctx, &reposerver.FileRequest{
+		RepoUrl: repo.Url,
+		RepoId:  strconv.FormatInt(int64(repo.ID), 10),
+		Branch:  repo.Branch,
+		Path:    application.CONFIG_FILE,
+	})
+
+	if status.Code(err) == codes.NotFound {
+		return application.RepoConfig{}, nil, nil
+	}
+
+	if err != nil {
+		return application.RepoConfig{}, nil, err
+	}
+
+	if file.Binary {
+		return application.RepoConfig{}, []string{application.CONFIG_FILE + " is not a text file"}, nil
+	}
+
+	if file.Truncated {
+		return application.RepoConfig{}, []string{fmt.Sprintf("%s is too large, %d bytes", application.CONFIG_FILE, file.Size)}, nil
+	}
+
+	config, problems := application.ParseRepoConfig(file.Content)
+
+	for i, appConfig := range config.Applications {
+		workload, err := client.ResolveWorkload(appConfig.Workload)
+
+		if err == nil {
+			err = s.clientset.CheckWorkloadAccess(ctx, workload)
+		}
+
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("applications[%d] (%s): %v", i, appConfig.Name, err))
+			continue
+		}
+
+		config.Applications[i].Workload = workload
+	}
+
+	return config, problems, nil
+}
@@ -0,0 +1,162 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/kubefill/kubefill/pkg/application"
+	"github.com/kubefill/kubefill/pkg/client"
+	"github.com/kubefill/kubefill/pkg/db"
+	"github.com/kubefill/kubefill/pkg/job"
+	"github.com/kubefill/kubefill/pkg/secret"
+	"github.com/kubefill/kubefill/pkg/tmpl"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// runJob renders a run of the application from input and starts it, off
+// ref, pinned to its commit, or the repo branch when it is empty. Problems
+// of the run itself are InvalidArgument, a run over the concurrency of the
+// application FailedPrecondition and template errors a tmpl.RenderError.
+func (s *Server) runJob(ctx context.Context, jobService *job.JobService, secretService *secret.SecretService, app db.Application, ref string, overlay string, input map[string]interface{}) (JobRunResponse, error) {
+	runResp := JobRunResponse{}
+	secretsMap := make(map[string]string)
+
+	for _, sc := range secretService.GetAllByAppId(app.ID) {
+		decrypted, err := s.keyring.decrypt(sc.Value)
+
+		if err != nil {
+			return runResp, err
+		}
+
+		secretsMap[sc.Name] = decrypted
+	}
+
+	rendered, commit, err := s.renderRun(ctx, app, ref, overlay, input, secretsMap)
+
+	if err != nil {
+		return runResp, err
+	}
+
+	if _, missing := unresolvedSecrets(rendered, app.RequiredSecrets, secretsMap); len(missing) > 0 {
+		return runResp, status.Errorf(codes.InvalidArgument, "secrets required or referenced by the manifest are not set: %s", strings.Join(missing, ", "))
+	}
+
+	object := unstructured.Unstructured{Object: rendered.Job}
+	jobName := fmt.Sprintf("%s-%s", object.GetName(), generateRandomString(12, charset))
+
+	// The job keeps the spec with its secret references unresolved.
+	spec, _ := json.Marshal(rendered.Job["spec"])
+	meta, _ := json.Marshal(rendered.Job["metadata"])
+
+	if err := injectSecrets(rendered, app.Workload, jobName+"-secrets", secretsMap); err != nil {
+		return runResp, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	var jobConfig client.JobConfig
+
+	if app.Workload == nil {
+		jobConfig, err = toJobConfig(rendered.Job, rendered.Resources)
+
+		if err != nil {
+			return runResp, status.Error(codes.InvalidArgument, err.Error())
+		}
+	}
+
+	newJob, err := jobService.CreateWithin(job.Job{Name: jobName, ApplicationID: app.ID, Ref: ref, Commit: commit}, app.Concurrency)
+	var concurrencyError *job.ConcurrencyError
+
+	if errors.As(err, &concurrencyError) {
+		return runResp, status.Error(codes.FailedPrecondition, err.Error())
+	}
+
+	if err != nil {
+		return runResp, err
+	}
+
+	newJob.Spec = spec
+	newJob.Meta = meta
+	jobId := strconv.FormatUint(uint64(newJob.ID), 10)
+	labels := make(map[string]string)
+
+	labels["invoked"] = ""
+	labels["job_id"] = jobId
+
+	jobRootLogsPath := filepath.Join(s.LogsPath, jobId)
+	err = os.RemoveAll(jobRootLogsPath)
+
+	if err != nil {
+		log.Errorln(err)
+	}
+
+	var resources []client.ObjectRef
+
+	if app.Workload == nil {
+		jobConfig.Labels = labels
+		resp, created, runErr := s.clientset.Run(jobName, jobConfig)
+		err = runErr
+
+		if err == nil {
+			// Supporting Secrets hold secret values, the created objects are
+			// listed in the job.
+			jobConfig.Resources = nil
+			resources = created
+			runResp.Config = jobConfig
+			runResp.Spec = resp.Spec
+			runResp.Status = resp.Status
+		}
+	} else {
+		newJob.Kind = application.WorkloadRef(app)
+		resp, created, runErr := s.clientset.RunWorkload(jobName, labels, app.Workload, rendered.Job, rendered.Resources)
+		err = runErr
+
+		if err == nil {
+			s.watchWorkload(app.Workload)
+			resources = created
+			runResp.Object = resp.Object
+		}
+	}
+
+	if len(resources) > 0 {
+		newJob.Resources, _ = json.Marshal(resources)
+	}
+
+	if updateErr := jobService.UpdateRun(newJob); updateErr != nil {
+		log.Errorf("failed to save job %d: %v", newJob.ID, updateErr)
+	}
+
+	// A job which never started would count against the concurrency for
+	// ever.
+	if err != nil {
+		if phaseErr := jobService.SetPhase(newJob.ID, client.PHASE_FAILED); phaseErr != nil {
+			log.Errorf("failed to fail job %d: %v", newJob.ID, phaseErr)
+		}
+
+		return runResp, err
+	}
+
+	runResp.Job = newJob
+	return runResp, nil
+}
+
+// runErrorResponse answers a failed runJob or renderRun, template errors
+// with the line they are on.
+func runErrorResponse(rw http.ResponseWriter, err error) {
+	var renderError *tmpl.RenderError
+
+	if errors.As(err, &renderError) {
+		JSONError(rw, TemplateErrorResp{Message: renderError.Error(), Line: renderError.Line, Rendered: renderError.Rendered}, http.StatusBadRequest)
+		return
+	}
+
+	JSONError(rw, errorResp{Message: status.Convert(err).Message()}, grpcHttpStatus(err))
+}
@@ -0,0 +1,140 @@
+package server
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/kubefill/kubefill/pkg/db"
+	repoPkg "github.com/kubefill/kubefill/pkg/repo"
+	"github.com/kubefill/kubefill/reposerver"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// syncScheduler polls every repo at its own interval and runs the syncs on a
+// bounded pool of workers, so a slow remote only ever holds up one worker.
+type syncScheduler struct {
+	rp              reposerver.RepoServiceClient
+	repoService     *repoPkg.Service
+	defaultInterval time.Duration
+	timeout         time.Duration
+	queue           chan repoPkg.Repo
+	mu              sync.Mutex
+	inFlight        map[int]bool
+	lastPolled      map[int]time.Time
+	onSynced        func(rp reposerver.RepoServiceClient, repoId uint)
+	syncRequest     func(repo db.Repo) *reposerver.SyncRequest
+	onPolicyFailure func(repoId uint, err error) bool
+}
+
+func newSyncScheduler(rp reposerver.RepoServiceClient, repoService *repoPkg.Service, defaultInterval time.Duration, timeout time.Duration, onSynced func(rp reposerver.RepoServiceClient, repoId uint), syncRequest func(repo db.Repo) *reposerver.SyncRequest, onPolicyFailure func(repoId uint, err error) bool) *syncScheduler {
+	return &syncScheduler{
+		rp:              rp,
+		repoService:     repoService,
+		defaultInterval: defaultInterval,
+		timeout:         timeout,
+		queue:           make(chan repoPkg.Repo),
+		inFlight:        make(map[int]bool),
+		lastPolled:      make(map[int]time.Time),
+		onSynced:        onSynced,
+		syncRequest:     syncRequest,
+		onPolicyFailure: onPolicyFailure,
+	}
+}
+
+func (s *syncScheduler) run(workers int) {
+	for i := 0; i < workers; i++ {
+		go s.worker()
+	}
+
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+
+	for {
+		s.enqueueDue(time.Now())
+		<-ticker.C
+	}
+}
+
+func (s *syncScheduler) interval(repo repoPkg.Repo) time.Duration {
+	if repo.PollInterval == repoPkg.POLL_DEFAULT {
+		return s.defaultInterval
+	}
+
+	return time.Minute * time.Duration(repo.PollInterval)
+}
+
+func (s *syncScheduler) enqueueDue(now time.Time) {
+	for _, repo := range s.repoService.List() {
+		if repo.PollInterval == repoPkg.POLL_DISABLED {
+			continue
+		}
+
+		s.mu.Lock()
+		due := !s.inFlight[repo.Id] && now.Sub(s.lastPolled[repo.Id]) >= s.interval(repo)
+
+		if due {
+			s.inFlight[repo.Id] = true
+			s.lastPolled[repo.Id] = now
+		}
+
+		s.mu.Unlock()
+
+		if due {
+			// Workers pick repos up as they free up, the ticker waits meanwhile.
+			s.queue <- repo
+		}
+	}
+}
+
+func (s *syncScheduler) worker() {
+	for repo := range s.queue {
+		s.sync(repo)
+
+		s.mu.Lock()
+		delete(s.inFlight, repo.Id)
+		s.mu.Unlock()
+	}
+}
+
+func (s *syncScheduler) sync(repo repoPkg.Repo) {
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+
+	updateRepo, err := s.repoService.Get(uint(repo.Id))
+
+	if err != nil {
+		log.Errorln(err)
+		return
+	}
+
+	resp, err := s.rp.Sync(ctx, s.syncRequest(updateRepo))
+
+	if status.Code(err) == codes.Aborted {
+		log.Infof("skipping scheduled sync of repo %d: %s", repo.Id, status.Convert(err).Message())
+		return
+	}
+
+	if err != nil {
+		log.Errorf("failed to sync repo %d: %v", repo.Id, err)
+		s.onPolicyFailure(updateRepo.ID, err)
+		return
+	}
+
+	if resp.UpToDate {
+		if updateRepo.PolicyFailure != "" {
+			updateRepo.PolicyFailure = ""
+			s.repoService.Update(updateRepo)
+		}
+
+		return
+	}
+
+	updateRepo.Commit = resp.Commit
+	updateRepo.Hash = resp.Hash
+	updateRepo.PolicyFailure = ""
+	s.repoService.Update(updateRepo)
+	s.onSynced(s.rp, updateRepo.ID)
+}
@@ -7,19 +7,18 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
-	"strconv"
 	"strings"
 	"time"
 
 	"github.com/kubefill/kubefill/pkg/client"
 	"github.com/kubefill/kubefill/pkg/db"
+	"github.com/kubefill/kubefill/pkg/grpcauth"
 	"github.com/kubefill/kubefill/pkg/health"
 	"github.com/kubefill/kubefill/pkg/job"
 	"github.com/kubefill/kubefill/pkg/repo"
 	"github.com/kubefill/kubefill/pkg/secret"
 	"github.com/kubefill/kubefill/reposerver"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
 
 	"github.com/kubefill/kubefill/pkg/application"
 
@@ -37,18 +36,33 @@ type ServerConfig struct {
 	LogsPath              string
 	KubeConfig            string
 	SecretsKey            string
+	SecretsKeys           string
+	SecretsKeyId          string
+	SyncWorkers           int
+	SyncTimeout           time.Duration
+	PruneInterval         time.Duration
+	RepoServerAuth        grpcauth.Config
+	SMTPAddress           string
+	SMTPFrom              string
+	SMTPUsername          string
+	SMTPPassword          string
 }
 
 type Server struct {
 	ServerConfig
-	log             *log.Entry
-	refreshInterval int
-	db              *db.Connection
-	clientset       *client.Clientset
-	repoService     *repo.Service
-	router          *mux.Router
-	stopCh          chan struct{}
-	hub             *Hub
+	log                *log.Entry
+	refreshInterval    int
+	db                 *db.Connection
+	clientset          *client.Clientset
+	repoService        *repo.Service
+	applicationService *application.Service
+	router             *mux.Router
+	stopCh             chan struct{}
+	hub                *Hub
+	syncs              *syncTracker
+	informer           *client.Informer
+	repoDialer         *grpcauth.Dialer
+	keyring            *keyring
 }
 
 func NewServer(config ServerConfig) *Server {
@@ -60,19 +74,43 @@ func NewServer(config ServerConfig) *Server {
 		"postgres",
 	)
 	newDb := db.NewDb(dbConfig)
+	hub := newHub()
+	repoDialer, err := grpcauth.NewDialer(config.RepoServerAuth)
+
+	if err != nil {
+		log.Fatalf("Failed to load the repo server credentials: %v", err)
+	}
+
+	keys, err := newKeyring(config.SecretsKey, config.SecretsKeys, config.SecretsKeyId)
+
+	if err != nil {
+		log.Fatalf("Failed to load the secrets keys: %v", err)
+	}
+
+	log.Infof("Secrets keys %v, encrypting with %q", keys.ids(), keys.current)
 
 	return &Server{
-		ServerConfig:    config,
-		db:              newDb,
-		log:             log.NewEntry(log.StandardLogger()),
-		refreshInterval: 15,
-		clientset:       client.NewClientset(),
-		repoService:     repo.NewService(newDb),
-		router:          mux.NewRouter().StrictSlash(true),
-		hub:             newHub(),
+		ServerConfig:       config,
+		db:                 newDb,
+		log:                log.NewEntry(log.StandardLogger()),
+		refreshInterval:    15,
+		clientset:          client.NewClientset(),
+		repoService:        repo.NewService(newDb),
+		applicationService: application.NewService(newDb),
+		router:             mux.NewRouter().StrictSlash(true),
+		hub:                hub,
+		syncs:              newSyncTracker(hub),
+		repoDialer:         repoDialer,
+		keyring:            keys,
 	}
 }
 
+// dialRepoServer connects to the reposerver with the configured TLS
+// credentials and service token.
+func (s *Server) dialRepoServer() (*grpc.ClientConn, error) {
+	return s.repoDialer.Dial(s.ServerConfig.RepoServerAddress)
+}
+
 func (s *Server) Init() {
 	var err error
 	uiFS, err = fs.Sub(ui.UI, "ui/build")
@@ -84,12 +122,27 @@ func (s *Server) Init() {
 	s.db.InitialMigration()
 }
 
+// watchWorkload makes the informer follow the phase of an application's
+// workload kind. Jobs are followed through their pods.
+func (s *Server) watchWorkload(workload *db.Workload) {
+	if workload == nil || s.informer == nil {
+		return
+	}
+
+	err := s.informer.WatchWorkload(workload.APIVersion, workload.Kind)
+
+	if err != nil {
+		log.Errorf("failed to watch %s %s: %v", workload.APIVersion, workload.Kind, err)
+	}
+}
+
 func (s *Server) Run() {
 	httpState := health.NewState()
 	jobService := job.NewService(s.db)
 	secretService := secret.NewService(s.db)
-	applicationService := application.NewService(s.db)
-	informer := client.NewInformer(s.clientset, jobService)
+	applicationService := s.applicationService
+	s.informer = client.NewInformer(s.clientset, jobService)
+	jobService.OnPhase(s.notifyPhase)
 	jwtKeySecret, err := s.clientset.CoreV1().Secrets("kubefill").Get(context.TODO(), "jwt", metav1.GetOptions{})
 
 	if err != nil {
@@ -101,15 +154,25 @@ func (s *Server) Run() {
 	s.router.HandleFunc("/api/v1/", s.apiRoot())
 	s.router.HandleFunc("/api/v1/repos", s.reposHandler(s.repoService))
 	s.router.HandleFunc("/api/v1/repos/{id:[0-9]+}", s.repoHandler(s.repoService))
+	s.router.HandleFunc("/api/v1/repos/{id:[0-9]+}/tree", s.repoTreeHandler(s.repoService))
+	s.router.HandleFunc("/api/v1/repos/{id:[0-9]+}/files", s.repoFileHandler(s.repoService))
+	s.router.HandleFunc("/api/v1/repos/{id:[0-9]+}/refs", s.repoRefsHandler(s.repoService))
+	s.router.HandleFunc("/api/v1/repos/{id:[0-9]+}/applications", s.repoApplicationsHandler(s.repoService))
+	s.router.HandleFunc("/api/v1/repos/{id:[0-9]+}/deploy-key", s.repoDeployKeyHandler(s.repoService))
+	s.router.HandleFunc("/api/v1/repos/{id:[0-9]+}/deploy-key/{action:confirm}", s.repoDeployKeyHandler(s.repoService))
 	s.router.HandleFunc("/api/v1/repos/{id:[0-9]+}/{action:[a-z]+}", s.repoHandler(s.repoService))
+	s.router.HandleFunc("/api/v1/hooks/git/{repoId:[0-9]+}", s.gitHookHandler(s.repoService))
 	s.router.HandleFunc("/api/v1/applications", s.applicationsHandler(applicationService))
 	s.router.HandleFunc("/api/v1/applications/{id:[0-9]+}", s.applicationHandler(applicationService, s.repoService))
+	s.router.HandleFunc("/api/v1/applications/{id:[0-9]+}/lint", s.applicationLintHandler(applicationService, s.repoService))
 	s.router.HandleFunc("/api/v1/applications/{id:[0-9]+}/jobs", s.applicationJobHandler(applicationService, jobService, secretService))
 	s.router.HandleFunc("/api/v1/applications/{id:[0-9]+}/secrets", s.applicationSecretsHandler(applicationService, secretService))
+	s.router.HandleFunc("/api/v1/applications/{id:[0-9]+}/secrets/preflight", s.applicationSecretsPreflightHandler(applicationService, secretService))
 	s.router.HandleFunc("/api/v1/applications/{appId:[0-9]+}/secrets/{secretId:[0-9]+}", s.applicationSecretHandler(applicationService, secretService))
 	s.router.HandleFunc("/api/v1/jobs/{id:[0-9]+}", s.jobHandler(jobService))
 	s.router.HandleFunc("/api/v1/jobs/{id:[0-9]+}/logs", s.logsHandler())
 	s.router.HandleFunc("/api/v1/settings", s.settingsHandler())
+	s.router.HandleFunc("/api/v1/admin/secrets/re-encrypt", s.reencryptHandler(secretService))
 
 	s.router.HandleFunc("/api/v1/auth/login", s.loginHandler())
 	s.router.HandleFunc("/api/v1/auth/self", s.selfHandler())
@@ -125,7 +188,7 @@ func (s *Server) Run() {
 
 	http.Handle("/", s.router)
 
-	conn, err := grpc.Dial(s.ServerConfig.RepoServerAddress, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	conn, err := s.dialRepoServer()
 
 	if err != nil {
 		log.Fatalf("%v", err)
@@ -135,37 +198,20 @@ func (s *Server) Run() {
 
 	rp := reposerver.NewRepoServiceClient(conn)
 
-	go func() {
-		ticker := time.NewTicker(time.Minute * time.Duration(s.refreshInterval))
-		defer ticker.Stop()
-
-		for range ticker.C {
-			repos := s.repoService.List()
-
-			for _, repo := range repos {
-				message := reposerver.SyncRequest{Repo: repo.Url, Branch: repo.Branch, RepoId: strconv.FormatInt(int64(repo.Id), 10)}
-				resp, err := rp.Sync(context.Background(), &message)
+	scheduler := newSyncScheduler(rp, s.repoService, time.Minute*time.Duration(s.refreshInterval), s.ServerConfig.SyncTimeout, s.afterSync, s.syncRequest, s.recordPolicyFailure)
+	go scheduler.run(s.ServerConfig.SyncWorkers)
 
-				if err != nil {
-					log.Errorln(err)
-					continue
-				}
-
-				updateRepo, err := s.repoService.Get(uint(repo.Id))
+	if s.ServerConfig.PruneInterval > 0 {
+		go s.pruneRepos(rp, s.ServerConfig.PruneInterval)
+	}
 
-				if err != nil {
-					log.Errorln(err)
-					continue
-				}
+	go s.informer.StartInformer(s.ServerConfig.LogsPath)
+	go s.runSchedules(jobService, secretService)
 
-				updateRepo.Commit = resp.Commit
-				updateRepo.Hash = resp.Hash
-				s.repoService.Update(updateRepo)
-			}
-		}
-	}()
+	for _, app := range applicationService.List() {
+		s.watchWorkload(app.Workload)
+	}
 
-	go informer.StartInformer(s.ServerConfig.LogsPath)
 	go func() {
 		log.Infof("Starting server...")
 		s.checkServeErr("http", http.ListenAndServe(":8080", nil))
@@ -0,0 +1,360 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/kubefill/kubefill/pkg/db"
+	"github.com/kubefill/kubefill/reposerver"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	SYNC_TOPIC        = "sync"
+	SYNC_PHASE_FAILED = "failed"
+	// SYNC_PHASE_POLICY_FAILED is published when the reposerver rejected the
+	// synced commit and kept the previous one.
+	SYNC_PHASE_POLICY_FAILED = "policy_failed"
+
+	// How long the events of a finished sync are kept for late subscribers.
+	syncRetention = 10 * time.Minute
+	// How many of the latest events of a sync are kept for replay.
+	syncMaxEvents = 200
+)
+
+// syncTracker records the events of running syncs and relays them to the
+// websocket room named after the sync id.
+type syncTracker struct {
+	mu     sync.Mutex
+	hub    *Hub
+	events map[string][]Message
+	// running maps a repo to the id of its running sync, rerun marks the
+	// repos to sync again once it finishes.
+	running map[uint]string
+	rerun   map[uint]bool
+}
+
+func newSyncTracker(hub *Hub) *syncTracker {
+	return &syncTracker{
+		hub:     hub,
+		events:  make(map[string][]Message),
+		running: make(map[uint]string),
+		rerun:   make(map[uint]bool),
+	}
+}
+
+// start registers a sync of the repo. When one is already running it
+// returns the id of that sync and false.
+func (t *syncTracker) start(repoId uint) (string, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if syncId, ok := t.running[repoId]; ok {
+		return syncId, false
+	}
+
+	syncId := generateRandomString(12, charset)
+	t.running[repoId] = syncId
+	t.events[syncId] = []Message{}
+	return syncId, true
+}
+
+// requestRerun makes the running sync of the repo start another one when it
+// finishes, so a push received meanwhile is not missed.
+func (t *syncTracker) requestRerun(repoId uint) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.rerun[repoId] = true
+}
+
+// finish ends the sync of the repo and reports whether a rerun was
+// requested meanwhile.
+func (t *syncTracker) finish(repoId uint, syncId string) bool {
+	t.mu.Lock()
+	delete(t.running, repoId)
+	rerun := t.rerun[repoId]
+	delete(t.rerun, repoId)
+	t.mu.Unlock()
+
+	time.AfterFunc(syncRetention, func() {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		delete(t.events, syncId)
+	})
+
+	return rerun
+}
+
+func (t *syncTracker) publish(event SyncEvent) {
+	message, err := json.Marshal(event)
+
+	if err != nil {
+		log.Errorln(err)
+		return
+	}
+
+	t.mu.Lock()
+	events := append(t.events[event.SyncId], message)
+
+	if len(events) > syncMaxEvents {
+		events = events[len(events)-syncMaxEvents:]
+	}
+
+	t.events[event.SyncId] = events
+	t.mu.Unlock()
+
+	// Sent without the lock, a busy hub must not block other syncs.
+	t.hub.broadcastToRoom <- RoomMessage{Message: message, Id: event.SyncId}
+}
+
+// subscribe joins the client to the room of its sync and replays the events
+// published so far. It returns false when the sync is unknown or expired.
+// The events are copied and the client joins under the lock, so an event
+// published meanwhile may arrive twice but is never missed, and the hub
+// replays them only while the client is connected.
+func (t *syncTracker) subscribe(c *Client) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	events, ok := t.events[c.Id]
+
+	if !ok {
+		return false
+	}
+
+	c.hub.joinRoom <- JoinRoom{client: c, replay: append([]Message(nil), events...)}
+	return true
+}
+
+// startSync starts a background sync of the repo. When the repo is already
+// syncing no second sync is started, it returns the running sync and false.
+func (s *Server) startSync(repo db.Repo) (string, bool) {
+	syncId, started := s.syncs.start(repo.ID)
+
+	if started {
+		go s.runSync(syncId, repo)
+	}
+
+	return syncId, started
+}
+
+func (s *Server) runSync(syncId string, repo db.Repo) {
+	defer func() {
+		if !s.syncs.finish(repo.ID, syncId) {
+			return
+		}
+
+		updateRepo, err := s.repoService.Get(repo.ID)
+
+		if err != nil {
+			log.Errorln(err)
+			return
+		}
+
+		log.Infof("Syncing repo %d again for changes pushed during sync %s", repo.ID, syncId)
+		s.startSync(updateRepo)
+	}()
+
+	fail := func(err error) {
+		log.Errorln(err)
+		phase := SYNC_PHASE_FAILED
+
+		if s.recordPolicyFailure(repo.ID, err) {
+			phase = SYNC_PHASE_POLICY_FAILED
+		}
+
+		s.syncs.publish(SyncEvent{SyncId: syncId, RepoId: repo.ID, Phase: phase, Error: status.Convert(err).Message()})
+	}
+
+	conn, err := s.dialRepoServer()
+
+	if err != nil {
+		fail(err)
+		return
+	}
+
+	defer conn.Close()
+
+	rp := reposerver.NewRepoServiceClient(conn)
+	message := s.syncRequest(repo)
+	ctx, cancel := context.WithTimeout(context.Background(), s.ServerConfig.SyncTimeout)
+	defer cancel()
+
+	stream, err := rp.SyncStream(ctx, message)
+
+	if err != nil {
+		fail(err)
+		return
+	}
+
+	for {
+		progress, err := stream.Recv()
+
+		if err == io.EOF {
+			return
+		}
+
+		if err != nil {
+			fail(err)
+			return
+		}
+
+		if progress.Phase == reposerver.SYNC_PHASE_DONE {
+			updateRepo, err := s.repoService.Get(repo.ID)
+
+			if err != nil {
+				fail(err)
+				return
+			}
+
+			updateRepo.Commit = progress.Commit
+			updateRepo.Hash = progress.Hash
+			updateRepo.PolicyFailure = ""
+			s.repoService.Update(updateRepo)
+		}
+
+		s.syncs.publish(SyncEvent{
+			SyncId:  syncId,
+			RepoId:  repo.ID,
+			Phase:   progress.Phase,
+			Message: progress.Message,
+			Hash:    progress.Hash,
+			Commit:  progress.Commit,
+		})
+
+		if progress.Phase == reposerver.SYNC_PHASE_DONE {
+			s.afterSync(rp, repo.ID)
+		}
+	}
+}
+
+// syncSparse syncs a sparse repo again, so that the paths of applications
+// created or moved since its last sync are checked out. A running sync is
+// followed by another one.
+func (s *Server) syncSparse(repoId uint) {
+	repo, err := s.repoService.Get(repoId)
+
+	if err != nil {
+		log.Errorln(err)
+		return
+	}
+
+	if !repo.Sparse {
+		return
+	}
+
+	if _, started := s.startSync(repo); !started {
+		s.syncs.requestRerun(repo.ID)
+	}
+}
+
+// signaturePolicy is the commit signature policy of a repo.
+func signaturePolicy(repo db.Repo) *reposerver.SignaturePolicy {
+	return &reposerver.SignaturePolicy{
+		Verify:      repo.VerifySignatures,
+		TrustedKeys: repo.TrustedKeys,
+	}
+}
+
+// repoDirRequest asks for the checkout of the repo branch, or of ref if set.
+func repoDirRequest(repo db.Repo, ref string) *reposerver.RepoDirRequest {
+	return &reposerver.RepoDirRequest{
+		RepoUrl:         repo.Url,
+		RepoId:          strconv.FormatInt(int64(repo.ID), 10),
+		Branch:          repo.Branch,
+		Ref:             ref,
+		SignaturePolicy: signaturePolicy(repo),
+	}
+}
+
+// syncRequest builds the sync request of a repo. Sparse repos only check out
+// the paths of their applications, or the whole tree while they have none.
+func (s *Server) syncRequest(repo db.Repo) *reposerver.SyncRequest {
+	message := reposerver.SyncRequest{
+		Repo:            repo.Url,
+		Branch:          repo.Branch,
+		RepoId:          strconv.FormatInt(int64(repo.ID), 10),
+		Depth:           int32(repo.Depth),
+		Submodules:      repo.Submodules,
+		Lfs:             repo.Lfs,
+		SignaturePolicy: signaturePolicy(repo),
+	}
+
+	if !repo.Sparse {
+		return &message
+	}
+
+	apps, err := s.applicationService.ListByRepoId(repo.ID)
+
+	if err != nil {
+		log.Errorln(err)
+		return &message
+	}
+
+	for _, app := range apps {
+		message.SparsePaths = append(message.SparsePaths, app.ManifestPath)
+
+		if app.ChartPath != "" {
+			message.SparsePaths = append(message.SparsePaths, app.ChartPath)
+		}
+	}
+
+	return &message
+}
+
+// recordPolicyFailure stores on the repo why the reposerver rejected its
+// synced commit. It returns false for any other error.
+func (s *Server) recordPolicyFailure(repoId uint, err error) bool {
+	if status.Code(err) != codes.PermissionDenied {
+		return false
+	}
+
+	repo, getErr := s.repoService.Get(repoId)
+
+	if getErr != nil {
+		log.Errorln(getErr)
+		return true
+	}
+
+	repo.PolicyFailure = status.Convert(err).Message()
+	s.repoService.Update(repo)
+	return true
+}
+
+// pruneRepos periodically removes the checkouts of the reposerver which no
+// repo tracks anymore.
+func (s *Server) pruneRepos(rp reposerver.RepoServiceClient, interval time.Duration) {
+	for range time.Tick(interval) {
+		repos, err := s.repoService.Find()
+
+		if err != nil {
+			log.Errorf("skipping prune of repo checkouts: %v", err)
+			continue
+		}
+
+		keep := make([]*reposerver.Checkout, len(repos))
+
+		for i, repo := range repos {
+			keep[i] = &reposerver.Checkout{RepoId: strconv.Itoa(repo.Id), Branch: repo.Branch}
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		resp, err := rp.PruneRepos(ctx, &reposerver.PruneReposRequest{Keep: keep})
+		cancel()
+
+		if err != nil {
+			log.Errorf("failed to prune repo checkouts: %v", err)
+			continue
+		}
+
+		if len(resp.Removed) > 0 {
+			log.Infof("Pruned %d orphaned repo checkouts", len(resp.Removed))
+		}
+	}
+}
@@ -3,6 +3,7 @@ package server
 import (
 	"time"
 
+	"github.com/kubefill/kubefill/pkg/application"
 	"github.com/kubefill/kubefill/pkg/client"
 	"github.com/kubefill/kubefill/pkg/db"
 	"github.com/kubefill/kubefill/reposerver"
@@ -15,9 +16,20 @@ type RepoResp struct {
 }
 
 type SettingsHttpResponse struct {
-	RepoRoot   string `json:"repo_root"`
-	SshRoot    string `json:"ssh_root"`
-	PrivateKey string `json:"private_key"`
+	RepoRoot     string `json:"repo_root"`
+	SshRoot      string `json:"ssh_root"`
+	PrivateKey   string `json:"private_key"`
+	SecretsKeyId string `json:"secrets_key_id"`
+}
+
+type ReencryptHttpResponse struct {
+	KeyId          string `json:"key_id"`
+	Secrets        int    `json:"secrets"`
+	WebhookSecrets int    `json:"webhook_secrets"`
+	// Legacy values are only rewritten when asked to, the key they are
+	// decrypted with can not be checked.
+	LegacyRewritten int `json:"legacy_rewritten"`
+	LegacySkipped   int `json:"legacy_skipped"`
 }
 
 type RepoHttpResponse struct {
@@ -31,15 +43,36 @@ type RepoHttpResponse struct {
 }
 
 type AppManifestHttpResp struct {
-	App       db.Application                `json:"app"`
-	Manifests *reposerver.ManifestsResponse `json:"manifests"`
+	App            db.Application                `json:"app"`
+	Manifests      *reposerver.ManifestsResponse `json:"manifests"`
+	ManifestsError string                        `json:"manifests_error,omitempty"`
+	Ref            string                        `json:"ref,omitempty"`
+	Commit         string                        `json:"commit,omitempty"`
+}
+
+type TemplateErrorResp struct {
+	Message  string `json:"message"`
+	Line     int    `json:"line,omitempty"`
+	Rendered bool   `json:"rendered,omitempty"`
+}
+
+type LintHttpResponse struct {
+	Valid  bool                        `json:"valid"`
+	Issues []*reposerver.ManifestIssue `json:"issues"`
+}
+
+type SecretsPreflightHttpResponse struct {
+	Referenced []string `json:"referenced"`
+	Set        []string `json:"set"`
+	Missing    []string `json:"missing"`
 }
 
 type JobRunResponse struct {
-	Job    db.Job           `json:"job"`
-	Config client.JobConfig `json:"config"`
-	Spec   v1.JobSpec       `json:"spec"`
-	Status v1.JobStatus     `json:"status"`
+	Job    db.Job                 `json:"job"`
+	Config client.JobConfig       `json:"config"`
+	Spec   v1.JobSpec             `json:"spec"`
+	Status v1.JobStatus           `json:"status"`
+	Object map[string]interface{} `json:"object,omitempty"`
 }
 
 type RetainedLogs struct {
@@ -75,3 +108,61 @@ type TokenRequest struct {
 type TokenHttpResponse struct {
 	Token string `json:"token"`
 }
+
+type DeployKeyHttpResponse struct {
+	DeployKey        string `json:"deploy_key"`
+	PendingDeployKey string `json:"pending_deploy_key"`
+}
+
+type SyncHttpResponse struct {
+	SyncId  string `json:"sync_id"`
+	Message string `json:"message,omitempty"`
+}
+
+type RefHttpResponse struct {
+	Name string `json:"name"`
+	Hash string `json:"hash"`
+}
+
+type RefsHttpResponse struct {
+	DefaultBranch string            `json:"default_branch"`
+	Branches      []RefHttpResponse `json:"branches"`
+	Tags          []RefHttpResponse `json:"tags"`
+}
+
+type SyncEvent struct {
+	SyncId  string `json:"sync_id"`
+	RepoId  uint   `json:"repo_id"`
+	Phase   string `json:"phase,omitempty"`
+	Message string `json:"message,omitempty"`
+	Hash    string `json:"hash,omitempty"`
+	Commit  string `json:"commit,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+type FileHttpResponse struct {
+	Hash      string `json:"hash"`
+	Path      string `json:"path"`
+	Size      int64  `json:"size"`
+	Binary    bool   `json:"binary"`
+	Truncated bool   `json:"truncated"`
+	Content   string `json:"content"`
+}
+
+type DiscoveredAppHttpResponse struct {
+	Name          string `json:"name"`
+	Path          string `json:"path"`
+	ApplicationID uint   `json:"application_id,omitempty"`
+}
+
+type DiscoverHttpResponse struct {
+	Hash         string                      `json:"hash"`
+	Applications []DiscoveredAppHttpResponse `json:"applications"`
+	Issues       []*reposerver.ManifestIssue `json:"issues"`
+}
+
+type ReconcileHttpResponse struct {
+	Created  []application.Application `json:"created"`
+	Removed  []uint                    `json:"removed"`
+	Problems []string                  `json:"problems"`
+}
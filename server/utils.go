@@ -2,10 +2,6 @@ package server
 
 import (
 	"bufio"
-	"crypto/aes"
-	"crypto/cipher"
-	cryptoRand "crypto/rand"
-	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -13,14 +9,21 @@ import (
 	"net/http"
 	"os"
 	"regexp"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/djherbis/times"
 	"github.com/golang/gddo/httputil/header"
 	"github.com/kubefill/kubefill/pkg/auth"
+	"github.com/kubefill/kubefill/pkg/client"
+	"github.com/kubefill/kubefill/pkg/db"
+	manifestPkg "github.com/kubefill/kubefill/pkg/manifest"
 	_ "github.com/lib/pq"
 	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 )
 
 type malformedRequest struct {
@@ -124,6 +127,27 @@ func decodeJSONBody(w http.ResponseWriter, r *http.Request, dst interface{}) err
 	return nil
 }
 
+// grpcHttpStatus maps the status of a failed reposerver call to the closest
+// HTTP status code.
+func grpcHttpStatus(err error) int {
+	switch status.Code(err) {
+	case codes.InvalidArgument:
+		return http.StatusBadRequest
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.FailedPrecondition, codes.AlreadyExists, codes.Aborted:
+		return http.StatusConflict
+	case codes.DeadlineExceeded:
+		return http.StatusGatewayTimeout
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	}
+
+	return http.StatusInternalServerError
+}
+
 func corsMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Access-Control-Allow-Origin", "*")
@@ -144,6 +168,7 @@ func check(result string) (bool, error) {
 		"api/v1/applications",
 		"api/v1/jobs",
 		"api/v1/settings",
+		"api/v1/admin",
 		"api/v1/auth/self",
 	}
 
@@ -197,6 +222,9 @@ func authMiddleware(jwtKey string) func(http.Handler) http.Handler {
 
 const charset = "abcdefghijklmnopqrstuvwxyz"
 
+// GitHub caps webhook payloads at 25MB.
+const maxWebhookBodySize = 25 << 20
+
 var seededRand *rand.Rand = rand.New(
 	rand.NewSource(time.Now().UnixNano()))
 
@@ -241,48 +269,139 @@ func statTimes(name string) (atime, mtime, ctime time.Time, err error) {
 	return
 }
 
-func encrypt(key []byte, message string) (string, error) {
-	byteMsg := []byte(message)
-	block, err := aes.NewCipher([]byte(key))
+// replaceSecrets substitutes {{secrets.NAME}} references in raw JSON.
+// Unknown secrets are replaced with an empty string.
+func replaceSecrets(raw string, secrets map[string]string) string {
+	return client.SecretRef.ReplaceAllStringFunc(raw, func(ref string) string {
+		return secrets[client.SecretRef.FindStringSubmatch(ref)[1]]
+	})
+}
 
-	if err != nil {
-		return "", fmt.Errorf("could not create new cipher: %v", err)
+// replaceSecretsIn substitutes secret references in the strings of a
+// decoded object, in place, so values are never escaped or parsed again. A
+// reference to a secret which is not set is an error.
+func replaceSecretsIn(value interface{}, secrets map[string]string) error {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			if s, ok := item.(string); ok {
+				replaced, err := replaceSecretsStrict(s, secrets)
+
+				if err != nil {
+					return err
+				}
+
+				v[key] = replaced
+			} else if err := replaceSecretsIn(item, secrets); err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		for i, item := range v {
+			if s, ok := item.(string); ok {
+				replaced, err := replaceSecretsStrict(s, secrets)
+
+				if err != nil {
+					return err
+				}
+
+				v[i] = replaced
+			} else if err := replaceSecretsIn(item, secrets); err != nil {
+				return err
+			}
+		}
 	}
 
-	cipherText := make([]byte, aes.BlockSize+len(byteMsg))
-	iv := cipherText[:aes.BlockSize]
+	return nil
+}
 
-	if _, err = io.ReadFull(cryptoRand.Reader, iv); err != nil {
-		return "", fmt.Errorf("could not encrypt: %v", err)
+func replaceSecretsStrict(s string, secrets map[string]string) (string, error) {
+	for _, match := range client.SecretRef.FindAllStringSubmatch(s, -1) {
+		if _, ok := secrets[match[1]]; !ok {
+			return "", fmt.Errorf("secret %s is not set", match[1])
+		}
 	}
 
-	stream := cipher.NewCFBEncrypter(block, iv)
-	stream.XORKeyStream(cipherText[aes.BlockSize:], byteMsg)
+	return replaceSecrets(s, secrets), nil
+}
+
+// secretRefs maps every secret to its {{secrets.NAME}} reference, which
+// templates render in place of the value.
+func secretRefs(secrets map[string]string) map[string]string {
+	refs := make(map[string]string, len(secrets))
 
-	return base64.StdEncoding.EncodeToString(cipherText), nil
+	for name := range secrets {
+		refs[name] = secretRef(name)
+	}
+
+	return refs
 }
 
-func decrypt(key []byte, message string) (string, error) {
-	cipherText, err := base64.StdEncoding.DecodeString(message)
-	if err != nil {
-		return "", fmt.Errorf("could not base64 decode: %v", err)
+func secretRef(name string) string {
+	return "{{secrets." + name + "}}"
+}
+
+// unresolvedSecrets returns the secrets the rendered run references and the
+// referenced or required ones which are not set, sorted.
+func unresolvedSecrets(rendered *manifestPkg.Manifest, required []string, secrets map[string]string) (referenced []string, missing []string) {
+	objects := []interface{}{rendered.Job}
+
+	for _, resource := range rendered.Resources {
+		objects = append(objects, resource)
 	}
 
-	block, err := aes.NewCipher(key)
+	referenced = client.SecretRefs(objects)
+	reported := make(map[string]bool)
 
-	if err != nil {
-		return "", fmt.Errorf("could not create new cipher: %v", err)
+	for _, name := range append(append([]string{}, referenced...), required...) {
+		if _, ok := secrets[name]; !ok && !reported[name] {
+			reported[name] = true
+			missing = append(missing, name)
+		}
 	}
 
-	if len(cipherText) < aes.BlockSize {
-		return "", fmt.Errorf("invalid ciphertext block size")
+	sort.Strings(missing)
+	return referenced, missing
+}
+
+// injectSecrets moves the secrets of a run out of its workload into the
+// Secret secretName, appended to the supporting objects. Supporting Secrets
+// get the values in place, no other supporting object may reference one.
+func injectSecrets(rendered *manifestPkg.Manifest, workload *db.Workload, secretName string, secrets map[string]string) error {
+	for i, resource := range rendered.Resources {
+		names := client.SecretRefs(resource)
+
+		if len(names) == 0 {
+			continue
+		}
+
+		u := unstructured.Unstructured{Object: resource}
+
+		if u.GetAPIVersion() != "v1" || u.GetKind() != "Secret" {
+			return fmt.Errorf("%s %s references secrets %s, only Secrets and the workload may", u.GetKind(), u.GetName(), strings.Join(names, ", "))
+		}
+
+		// data holds base64, which a plain value would not be.
+		if refs := client.SecretRefs(resource["data"]); len(refs) > 0 {
+			return fmt.Errorf("Secret %s references secrets %s in its data, reference them in its stringData", u.GetName(), strings.Join(refs, ", "))
+		}
+
+		err := replaceSecretsIn(rendered.Resources[i], secrets)
+
+		if err != nil {
+			return fmt.Errorf("Secret %s: %w", u.GetName(), err)
+		}
 	}
 
-	iv := cipherText[:aes.BlockSize]
-	cipherText = cipherText[aes.BlockSize:]
+	secret, err := client.InjectSecrets(rendered.Job, workload, secretName, secrets)
+
+	if err != nil {
+		return err
+	}
 
-	stream := cipher.NewCFBDecrypter(block, iv)
-	stream.XORKeyStream(cipherText, cipherText)
+	if secret != nil {
+		rendered.Resources = append(rendered.Resources, secret)
+	}
 
-	return string(cipherText), nil
+	return nil
 }
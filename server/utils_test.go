@@ -0,0 +1,156 @@
+package server
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	manifestPkg "github.com/kubefill/kubefill/pkg/manifest"
+)
+
+func TestReplaceSecretsIn(t *testing.T) {
+	secrets := map[string]string{"password": `p"a\ss`, "cert": "line1\nline2", "empty": ""}
+
+	tests := []struct {
+		name     string
+		value    interface{}
+		expected interface{}
+		err      string
+	}{
+		{
+			name:     "map values",
+			value:    map[string]interface{}{"password": "{{secrets.password}}", "dsn": "user:{{ secrets.password }}@db", "n": 1},
+			expected: map[string]interface{}{"password": `p"a\ss`, "dsn": `user:p"a\ss@db`, "n": 1},
+		},
+		{
+			name:     "nested",
+			value:    map[string]interface{}{"stringData": map[string]interface{}{"tls.crt": "{{secrets.cert}}", "list": []interface{}{"{{secrets.empty}}", "x"}}},
+			expected: map[string]interface{}{"stringData": map[string]interface{}{"tls.crt": "line1\nline2", "list": []interface{}{"", "x"}}},
+		},
+		{
+			name:     "no references",
+			value:    []interface{}{"a", map[string]interface{}{"b": "c"}},
+			expected: []interface{}{"a", map[string]interface{}{"b": "c"}},
+		},
+		{
+			name:  "unset secret",
+			value: map[string]interface{}{"a": "{{secrets.password}}", "b": []interface{}{"{{secrets.missing}}"}},
+			err:   "secret missing is not set",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := replaceSecretsIn(test.value, secrets)
+
+			if test.err != "" {
+				if err == nil || !strings.Contains(err.Error(), test.err) {
+					t.Fatalf("expected an error containing %q, got %v", test.err, err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !reflect.DeepEqual(test.value, test.expected) {
+				t.Fatalf("expected %v, got %v", test.expected, test.value)
+			}
+		})
+	}
+}
+
+func TestUnresolvedSecrets(t *testing.T) {
+	rendered := &manifestPkg.Manifest{
+		Job:       map[string]interface{}{"env": []interface{}{"{{secrets.b}}", "{{secrets.a}}"}},
+		Resources: []map[string]interface{}{{"stringData": map[string]interface{}{"x": "{{secrets.c}}"}}},
+	}
+	secrets := map[string]string{"a": "1"}
+
+	referenced, missing := unresolvedSecrets(rendered, []string{"d", "b", "a"}, secrets)
+
+	if expected := []string{"a", "b", "c"}; !reflect.DeepEqual(referenced, expected) {
+		t.Errorf("expected referenced %v, got %v", expected, referenced)
+	}
+
+	if expected := []string{"b", "c", "d"}; !reflect.DeepEqual(missing, expected) {
+		t.Errorf("expected missing %v, got %v", expected, missing)
+	}
+}
+
+func TestInjectSecretsResources(t *testing.T) {
+	job := func() map[string]interface{} {
+		return map[string]interface{}{
+			"spec": map[string]interface{}{"template": map[string]interface{}{"spec": map[string]interface{}{
+				"containers": []interface{}{map[string]interface{}{"name": "main"}},
+			}}},
+		}
+	}
+	secret := func(field string, value string) map[string]interface{} {
+		return map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "Secret",
+			"metadata":   map[string]interface{}{"name": "creds"},
+			field:        map[string]interface{}{"password": value},
+		}
+	}
+	secrets := map[string]string{"password": "p4ss"}
+
+	tests := []struct {
+		name      string
+		resources []map[string]interface{}
+		expected  []map[string]interface{}
+		err       string
+	}{
+		{
+			name:      "stringData",
+			resources: []map[string]interface{}{secret("stringData", "{{secrets.password}}")},
+			expected:  []map[string]interface{}{secret("stringData", "p4ss")},
+		},
+		{
+			name:      "data",
+			resources: []map[string]interface{}{secret("data", "{{secrets.password}}")},
+			err:       "in its data",
+		},
+		{
+			name:      "unset secret",
+			resources: []map[string]interface{}{secret("stringData", "{{secrets.missing}}")},
+			err:       "Secret creds: secret missing is not set",
+		},
+		{
+			name: "other kind",
+			resources: []map[string]interface{}{{
+				"apiVersion": "v1",
+				"kind":       "ConfigMap",
+				"metadata":   map[string]interface{}{"name": "config"},
+				"data":       map[string]interface{}{"password": "{{secrets.password}}"},
+			}},
+			err: "ConfigMap config references secrets password",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rendered := &manifestPkg.Manifest{Job: job(), Resources: test.resources}
+			err := injectSecrets(rendered, nil, "run-secrets", secrets)
+
+			if test.err != "" {
+				if err == nil || !strings.Contains(err.Error(), test.err) {
+					t.Fatalf("expected an error containing %q, got %v", test.err, err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !reflect.DeepEqual(rendered.Resources, test.expected) {
+				t.Fatalf("expected %v, got %v", test.expected, rendered.Resources)
+			}
+		})
+	}
+}
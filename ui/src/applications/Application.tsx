@@ -9,6 +9,7 @@ import { FormikValues } from "formik";
 import { fetchRepos } from "../requests/repos";
 import { getErrorMessage } from "../requests/utils";
 import {
+  Alert,
   Button,
   Container,
   Dialog,
@@ -124,6 +125,8 @@ const Application = () => {
           setFormDefaults({
             name: data.app.name,
             manifest_path: data.app.manifest_path,
+            manifest_type: data.app.manifest_type || "static",
+            chart_path: data.app.chart_path,
             repo_id: data.app.repo_id,
           });
         })
@@ -203,7 +206,7 @@ const Application = () => {
         {!application && <HorizontalFiller />}
         <Actions>
           <LoadingAction
-            disabled={deleting}
+            disabled={deleting || application?.app.git_owned}
             loading={deleting}
             onClick={handleDelete}
             color="error"
@@ -214,7 +217,7 @@ const Application = () => {
           </LoadingAction>
 
           <LoadingAction
-            disabled={!formValid}
+            disabled={!formValid || application?.app.git_owned}
             loading={updating}
             onClick={handleUpdate}
             color="primary"
@@ -244,6 +247,12 @@ const Application = () => {
         </Actions>
       </WorkspaceNavBar>
 
+      {application?.app.git_owned && (
+        <Alert severity="info" sx={{ mt: 3, mx: 3 }}>
+          This application is declared in the .kubefill.yaml of its repo and can only be changed there.
+        </Alert>
+      )}
+
       {repos && formDefaults && (
         <FormContainer sx={{ mt: 3 }} maxWidth={false}>
           <ApplicationForm
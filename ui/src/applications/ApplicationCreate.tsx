@@ -20,6 +20,8 @@ const FormContainer = styled(Container)`
 const formDefaults = {
   name: "",
   manifest_path: "",
+  manifest_type: "static",
+  chart_path: "",
   repo_id: "",
 };
 
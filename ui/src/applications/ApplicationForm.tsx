@@ -87,10 +87,50 @@ const ApplicationForm = ({
         )}
       </FormControl>
 
+      <FormControl fullWidth={true} sx={{ mb: 2 }}>
+        <InputLabel id="manifest-type-label">Manifest type</InputLabel>
+        <Select
+          labelId="manifest-type-label"
+          id="manifest_type"
+          name="manifest_type"
+          label="Manifest type"
+          value={formik.values?.manifest_type || "static"}
+          onChange={formik.handleChange}
+          onBlur={formik.handleBlur}
+        >
+          <MenuItem value="static">Static manifests</MenuItem>
+          <MenuItem value="helm">Helm chart</MenuItem>
+          <MenuItem value="kustomize">Kustomize overlays</MenuItem>
+          <MenuItem value="template">Go template</MenuItem>
+        </Select>
+      </FormControl>
+
+      {formik.values?.manifest_type === "helm" && (
+        <Box sx={{ mb: 2 }}>
+          <TextField
+            fullWidth={true}
+            required={true}
+            error={!!formik.touched?.chart_path && !!formik.errors?.chart_path}
+            id="chart_path"
+            name="chart_path"
+            label="Chart path"
+            value={formik.values?.chart_path || ""}
+            onChange={formik.handleChange}
+            onBlur={formik.handleBlur}
+          />
+
+          {formik.touched?.chart_path && formik.errors?.chart_path && (
+            <FormHelperText id="chart-path-error-text">
+              {formik.errors?.chart_path as string}
+            </FormHelperText>
+          )}
+        </Box>
+      )}
+
       <Box sx={{ mb: 2 }}>
         <TextField
           fullWidth={true}
-          required={true}
+          required={formik.values?.manifest_type !== "helm"}
           error={!!formik.touched?.manifest_path && !!formik.errors?.manifest_path}
           id="manifest_path"
           name="manifest_path"
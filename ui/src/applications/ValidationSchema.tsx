@@ -6,5 +6,14 @@ export const ValidationSchema = Yup.object({
     .max(100, "Name should be 4-100 chars long")
     .required("Input required"),
   repo_id: Yup.string().required("Input required"),
-  manifest_path: Yup.string().required("Input required"),
+  manifest_type: Yup.string().oneOf(["static", "helm", "kustomize", "template"]),
+  manifest_path: Yup.string().when("manifest_type", {
+    is: "helm",
+    then: Yup.string(),
+    otherwise: Yup.string().required("Input required"),
+  }),
+  chart_path: Yup.string().when("manifest_type", {
+    is: "helm",
+    then: Yup.string().required("Input required"),
+  }),
 });
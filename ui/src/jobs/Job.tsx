@@ -225,6 +225,15 @@ const Job = () => {
                 variant="outlined"
               />
             )}
+
+            {job.ref && (
+              <Chip
+                size="small"
+                label={`${job.ref} @ ${job.commit?.substring(0, 7)}`}
+                title="Ran off another ref than the repo branch"
+                variant="outlined"
+              />
+            )}
           </JobHeader>
           <Logs ws={ws} job={job} />
         </JobContainer>
@@ -48,6 +48,15 @@ const Jobs: FunctionComponent = (): ReactElement => {
         return result.join(", ");
       },
     },
+    {
+      field: "ref",
+      headerName: "REF",
+      flex: 0.2,
+      width: 100,
+      valueGetter: (params) => {
+        return params.row?.ref ? `${params.row.ref} @ ${params.row.commit?.substring(0, 7)}` : "";
+      },
+    },
     {
       field: "created",
       headerName: "CREATED ON",
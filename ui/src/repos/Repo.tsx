@@ -1,10 +1,18 @@
 import { FunctionComponent, ReactElement, useEffect, useState } from "react";
 import { Repo as RepoType } from "../types";
-import { fetchRepo, syncRepo, updateRepo } from "../requests/repos";
+import {
+  confirmDeployKey,
+  fetchRepo,
+  followSync,
+  generateDeployKey,
+  syncRepo,
+  updateRepo,
+} from "../requests/repos";
 import { deleteRepo } from "../requests/repos";
 import { useNavigate, useParams } from "react-router-dom";
 import { Crumbs } from "../Crumbs";
 import {
+  Alert,
   Button,
   Container,
   Dialog,
@@ -36,12 +44,14 @@ const Repo: FunctionComponent = (): ReactElement => {
   const [updating, setUpdating] = useState<boolean>(false);
   const [loading, setLoading] = useState<boolean>(false);
   const [deleting, setDelelting] = useState<boolean>(false);
+  const [keyLoading, setKeyLoading] = useState<boolean>(false);
   const [repo, setRepo] = useState<RepoType>();
   const [formValues, setFormValues] = useState<Partial<any>>();
   const [formValid, setFormValid] = useState(false);
   const [formDefaults, setFormDefaults] = useState<any>();
   const { enqueueSnackbar } = useSnackbar();
   const [open, setOpen] = useState(false);
+  const [inUse, setInUse] = useState<string>();
   const theme = useTheme();
   const fullScreen = useMediaQuery(theme.breakpoints.down("md"));
   const navigate = useNavigate();
@@ -52,9 +62,11 @@ const Repo: FunctionComponent = (): ReactElement => {
 
   const handleConfirmDelete = () => {
     if (repoId) {
+      const cascade = inUse !== undefined;
       setOpen(false);
+      setInUse(undefined);
       setDelelting(true);
-      deleteRepo(repoId)
+      deleteRepo(repoId, cascade)
         .then(() => {
           enqueueSnackbar("Deleted", {
             variant: "success",
@@ -63,6 +75,13 @@ const Repo: FunctionComponent = (): ReactElement => {
         })
         .catch((err) => {
           err.json().then((resp: any) => {
+            // The repo still has applications, offer to delete them too.
+            if (err.status === 409 && !cascade && resp.message?.includes("cascade")) {
+              setInUse(getErrorMessage(resp));
+              setOpen(true);
+              return;
+            }
+
             enqueueSnackbar(getErrorMessage(resp), {
               variant: "error",
             });
@@ -78,9 +97,42 @@ const Repo: FunctionComponent = (): ReactElement => {
     if (repoId) {
       setLoading(true);
       syncRepo(repoId)
-        .then((updateResp: any) => {
-          setRepo(updateResp);
-          enqueueSnackbar("Synced", {
+        .then((syncResp) => {
+          followSync(syncResp.sync_id, (event) => {
+            if (event.phase === "done") {
+              fetchRepo(repoId).then((data) => setRepo(data));
+              enqueueSnackbar("Synced", {
+                variant: "success",
+              });
+              setLoading(false);
+            }
+
+            if (event.phase === "failed") {
+              enqueueSnackbar(event.error, {
+                variant: "error",
+              });
+              setLoading(false);
+            }
+          });
+        })
+        .catch((err) => {
+          err.json().then((resp: any) => {
+            enqueueSnackbar(getErrorMessage(resp), {
+              variant: "error",
+            });
+          });
+          setLoading(false);
+        });
+    }
+  };
+
+  const handleDeployKey = (confirm: boolean) => {
+    if (repoId && repo) {
+      setKeyLoading(true);
+      (confirm ? confirmDeployKey(repoId) : generateDeployKey(repoId))
+        .then((keys) => {
+          setRepo({ ...repo, ...keys });
+          enqueueSnackbar(confirm ? "Deploy key confirmed" : "Deploy key generated", {
             variant: "success",
           });
         })
@@ -92,7 +144,7 @@ const Repo: FunctionComponent = (): ReactElement => {
           });
         })
         .finally(() => {
-          setLoading(false);
+          setKeyLoading(false);
         });
     }
   };
@@ -126,6 +178,7 @@ const Repo: FunctionComponent = (): ReactElement => {
 
   const handleClose = () => {
     setOpen(false);
+    setInUse(undefined);
   };
 
   useEffect(() => {
@@ -136,6 +189,13 @@ const Repo: FunctionComponent = (): ReactElement => {
           setFormDefaults({
             url: data.url,
             branch: data.branch,
+            poll_interval: data.poll_interval,
+            depth: data.depth,
+            submodules: data.submodules,
+            sparse: data.sparse,
+            lfs: data.lfs,
+            verify_signatures: data.verify_signatures,
+            trusted_keys: data.trusted_keys || [],
           });
         })
         .catch((err) => {
@@ -162,6 +222,7 @@ const Repo: FunctionComponent = (): ReactElement => {
       >
         <DialogTitle id="responsive-dialog-title">{"Confirm delete?"}</DialogTitle>
         <DialogContent>
+          {inUse && <Alert severity="warning">{inUse}</Alert>}
           <DialogContentText>This action can't be undone.</DialogContentText>
         </DialogContent>
         <DialogActions>
@@ -169,7 +230,7 @@ const Repo: FunctionComponent = (): ReactElement => {
             Cancel
           </Button>
           <Button autoFocus={true} onClick={handleConfirmDelete}>
-            Delete
+            {inUse ? "Delete with applications" : "Delete"}
           </Button>
         </DialogActions>
       </Dialog>
@@ -247,6 +308,72 @@ const Repo: FunctionComponent = (): ReactElement => {
           </>
         )}
 
+        {repo?.policy_failure && (
+          <Alert severity="error" sx={{ mt: 1 }}>
+            {repo.policy_failure}
+          </Alert>
+        )}
+
+        {repo?.config_errors && repo.config_errors.length > 0 && (
+          <Alert severity="warning" sx={{ mt: 1 }}>
+            .kubefill.yaml was not applied:
+            <ul>
+              {repo.config_errors.map((problem) => (
+                <li key={problem}>{problem}</li>
+              ))}
+            </ul>
+          </Alert>
+        )}
+
+        {repo?.discovery_errors && repo.discovery_errors.length > 0 && (
+          <Alert severity="warning" sx={{ mt: 1 }}>
+            Manifest directories which do not parse, their applications are kept:
+            <ul>
+              {repo.discovery_errors.map((problem) => (
+                <li key={problem}>{problem}</li>
+              ))}
+            </ul>
+          </Alert>
+        )}
+
+        <Typography variant="body1" fontWeight={600} gutterBottom={true} sx={{ mt: 2 }}>
+          Deploy key
+        </Typography>
+
+        {repo?.deploy_key && (
+          <Typography variant="body2" gutterBottom={true} sx={{ wordBreak: "break-all" }}>
+            {repo.deploy_key}
+          </Typography>
+        )}
+
+        {repo?.pending_deploy_key && (
+          <Alert severity="info" sx={{ mt: 1, wordBreak: "break-all" }}>
+            Add this key to the git host, then confirm it. The current key stays in use until then.
+            <br />
+            {repo.pending_deploy_key}
+          </Alert>
+        )}
+
+        <Box sx={{ mt: 1, display: "flex", gap: 1 }}>
+          <Button
+            variant="outlined"
+            disabled={keyLoading}
+            onClick={() => handleDeployKey(false)}
+          >
+            {repo?.deploy_key ? "Rotate" : "Generate"}
+          </Button>
+
+          {repo?.pending_deploy_key && (
+            <Button
+              variant="outlined"
+              disabled={keyLoading}
+              onClick={() => handleDeployKey(true)}
+            >
+              Confirm
+            </Button>
+          )}
+        </Box>
+
         {repo && formDefaults && (
           <Box sx={{ mt: 3 }}>
             <RepoForm
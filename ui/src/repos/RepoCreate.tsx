@@ -20,6 +20,14 @@ const formDefaults = {
   url: "",
   branch: "",
   ssh_private_key: "",
+  webhook_secret: "",
+  poll_interval: 0,
+  depth: 0,
+  submodules: false,
+  sparse: false,
+  lfs: false,
+  verify_signatures: false,
+  trusted_keys: [],
 };
 
 const RepoCreate = () => {
@@ -1,8 +1,9 @@
-import { useEffect } from "react";
-import { Box, FormHelperText } from "@mui/material";
+import { ChangeEvent, useEffect, useState } from "react";
+import { Box, Checkbox, FormControlLabel, FormGroup, FormHelperText } from "@mui/material";
 import { FormikValues, useFormik } from "formik";
 import { CreateValidationSchema, UpdateValidationSchema } from "./ValidationSchemas";
-import { RepoCreate } from "../types";
+import { RepoCreate, Refs } from "../types";
+import { fetchRepoRefs } from "../requests/repos";
 import { TextField } from "../components/TextField";
 
 type RepoFormParams = {
@@ -12,6 +13,21 @@ type RepoFormParams = {
   handleValueUpdate: (values: FormikValues) => void;
 };
 
+const PGP_BLOCK = /-----BEGIN PGP PUBLIC KEY BLOCK-----[\s\S]*?-----END PGP PUBLIC KEY BLOCK-----/g;
+
+// splitTrustedKeys returns the armored GPG keys and the SSH public key lines
+// of the trusted keys text.
+const splitTrustedKeys = (text: string) => {
+  const pgpKeys = text.match(PGP_BLOCK) || [];
+  const sshKeys = text
+    .replace(PGP_BLOCK, "")
+    .split("\n")
+    .map((line) => line.trim())
+    .filter((line) => line.length > 0);
+
+  return [...pgpKeys, ...sshKeys];
+};
+
 const RepoForm = ({ repoId, initialValues, formValid, handleValueUpdate }: RepoFormParams) => {
   const formik = useFormik({
     initialValues: initialValues,
@@ -33,6 +49,26 @@ const RepoForm = ({ repoId, initialValues, formValid, handleValueUpdate }: RepoF
     handleValueUpdate(formik.values);
   }, [formik.values, handleValueUpdate]);
 
+  const [refs, setRefs] = useState<Refs>();
+
+  useEffect(() => {
+    if (!repoId) {
+      return;
+    }
+
+    // The branches are a hint only, the server validates the branch on save.
+    fetchRepoRefs(String(repoId))
+      .then(setRefs)
+      .catch(() => setRefs(undefined));
+  }, [repoId]);
+
+  const [trustedKeys, setTrustedKeys] = useState((initialValues.trusted_keys || []).join("\n"));
+
+  const handleTrustedKeysChange = (event: ChangeEvent<HTMLInputElement>) => {
+    setTrustedKeys(event.target.value);
+    formik.setFieldValue("trusted_keys", splitTrustedKeys(event.target.value));
+  };
+
   return (
     <Box component="form" noValidate autoComplete="off">
       <Box sx={{ mb: 2 }}>
@@ -57,20 +93,24 @@ const RepoForm = ({ repoId, initialValues, formValid, handleValueUpdate }: RepoF
       <Box sx={{ mb: 2 }}>
         <TextField
           fullWidth={true}
-          required={true}
           error={!!formik.touched?.branch && !!formik.errors?.branch}
           id="branch"
           name="branch"
           size="small"
           label="Branch"
+          placeholder={refs?.default_branch || "Default branch of the remote"}
           value={formik.values?.branch || ""}
           onChange={formik.handleChange}
           onBlur={formik.handleBlur}
         />
 
-        {formik.touched?.branch && formik.errors?.branch && (
-          <FormHelperText id="name-error-text">{formik.errors?.branch}</FormHelperText>
-        )}
+        <FormHelperText id="branch-helper-text">
+          {formik.touched?.branch && formik.errors?.branch
+            ? formik.errors?.branch
+            : refs
+            ? `Branches: ${refs.branches.map((ref) => ref.name).join(", ") || "none"}`
+            : "Leave empty to track the default branch of the remote"}
+        </FormHelperText>
       </Box>
 
       <Box sx={{ mt: 2 }}>
@@ -95,6 +135,135 @@ const RepoForm = ({ repoId, initialValues, formValid, handleValueUpdate }: RepoF
           </FormHelperText>
         )}
       </Box>
+
+      <Box sx={{ mt: 2 }}>
+        <TextField
+          fullWidth={true}
+          id="poll_interval"
+          name="poll_interval"
+          size="small"
+          type="number"
+          label="Poll interval (minutes)"
+          error={!!formik.touched?.poll_interval && !!formik.errors?.poll_interval}
+          value={formik.values?.poll_interval ?? 0}
+          onChange={formik.handleChange}
+          onBlur={formik.handleBlur}
+        />
+
+        <FormHelperText id="poll-interval-helper-text">
+          {formik.touched?.poll_interval && formik.errors?.poll_interval
+            ? formik.errors?.poll_interval
+            : "0 uses the server default, -1 disables polling"}
+        </FormHelperText>
+      </Box>
+
+      <Box sx={{ mt: 2 }}>
+        <TextField
+          fullWidth={true}
+          id="depth"
+          name="depth"
+          size="small"
+          type="number"
+          label="Clone depth"
+          error={!!formik.touched?.depth && !!formik.errors?.depth}
+          value={formik.values?.depth ?? 0}
+          onChange={formik.handleChange}
+          onBlur={formik.handleBlur}
+        />
+
+        <FormHelperText id="depth-helper-text">
+          {formik.touched?.depth && formik.errors?.depth
+            ? formik.errors?.depth
+            : "0 clones the full history"}
+        </FormHelperText>
+
+        <FormGroup row>
+          <FormControlLabel
+            label="Submodules"
+            control={
+              <Checkbox
+                name="submodules"
+                checked={!!formik.values?.submodules}
+                onChange={formik.handleChange}
+              />
+            }
+          />
+          <FormControlLabel
+            label="Sparse checkout"
+            control={
+              <Checkbox
+                name="sparse"
+                checked={!!formik.values?.sparse}
+                onChange={formik.handleChange}
+              />
+            }
+          />
+          <FormControlLabel
+            label="Git LFS"
+            control={
+              <Checkbox name="lfs" checked={!!formik.values?.lfs} onChange={formik.handleChange} />
+            }
+          />
+        </FormGroup>
+
+        <FormHelperText id="sparse-helper-text">
+          Sparse checkouts only contain the paths of the repo's applications
+        </FormHelperText>
+      </Box>
+
+      <Box sx={{ mt: 2 }}>
+        <FormControlLabel
+          label="Only sync signed commits"
+          control={
+            <Checkbox
+              name="verify_signatures"
+              checked={!!formik.values?.verify_signatures}
+              onChange={formik.handleChange}
+            />
+          }
+        />
+
+        {formik.values?.verify_signatures && (
+          <>
+            <TextField
+              id="trusted_keys"
+              name="trusted_keys"
+              label="Trusted signing keys"
+              fullWidth={true}
+              multiline={true}
+              rows={6}
+              size="small"
+              error={!!formik.errors?.trusted_keys}
+              value={trustedKeys}
+              onChange={handleTrustedKeysChange}
+            />
+
+            <FormHelperText id="trusted-keys-helper-text">
+              {formik.errors?.trusted_keys
+                ? String(formik.errors?.trusted_keys)
+                : "Armored GPG public keys and SSH public keys, one per line"}
+            </FormHelperText>
+          </>
+        )}
+      </Box>
+
+      <Box sx={{ mt: 2 }}>
+        <TextField
+          fullWidth={true}
+          id="webhook_secret"
+          name="webhook_secret"
+          size="small"
+          type="password"
+          label="Webhook secret"
+          value={formik.values?.webhook_secret || ""}
+          onChange={formik.handleChange}
+          onBlur={formik.handleBlur}
+        />
+
+        <FormHelperText id="webhook-secret-helper-text">
+          Verifies push webhooks sent to /api/v1/hooks/git/{repoId || "<repo id>"}
+        </FormHelperText>
+      </Box>
     </Box>
   );
 };
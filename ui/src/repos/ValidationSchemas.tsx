@@ -3,20 +3,32 @@ import * as Yup from "yup";
 export const CreateValidationSchema = Yup.object({
   url: Yup.string()
     .matches(
-      /((git|ssh|http(s)?)|(git@[\w.]+))(:(\/\/)?)([\w.@:/\-~]+)(\.git)(\/)?$/,
-      "Enter an SSH URL, like git@github.com:user/repo.git"
+      /^(((git|ssh|git\+ssh|http(s)?|file):\/\/[\w.@:/\-~]+)|([\w.\-]+@)?[\w.\-]+:[\w.@:/\-~]+|\/[\w.@:/\-~]+)$/,
+      "Enter a git URL, like git@github.com:user/repo.git or file:///srv/git/repo"
     )
     .required("Input required"),
-  branch: Yup.string().required("Input required"),
-  ssh_private_key: Yup.string().required("Input required"),
+  branch: Yup.string(),
+  poll_interval: Yup.number().integer().min(-1, "Use -1 to disable polling"),
+  depth: Yup.number().integer().min(0, "Use 0 to clone the full history"),
+  trusted_keys: Yup.array().when("verify_signatures", {
+    is: true,
+    then: Yup.array().min(1, "Add at least one trusted key"),
+  }),
+  ssh_private_key: Yup.string(),
 });
 
 export const UpdateValidationSchema = Yup.object({
   url: Yup.string()
     .matches(
-      /((git|ssh|http(s)?)|(git@[\w.]+))(:(\/\/)?)([\w.@:/\-~]+)(\.git)(\/)?$/,
-      "Enter an SSH URL, like git@github.com:user/repo.git"
+      /^(((git|ssh|git\+ssh|http(s)?|file):\/\/[\w.@:/\-~]+)|([\w.\-]+@)?[\w.\-]+:[\w.@:/\-~]+|\/[\w.@:/\-~]+)$/,
+      "Enter a git URL, like git@github.com:user/repo.git or file:///srv/git/repo"
     )
     .required("Input required"),
-  branch: Yup.string().required("Input required"),
+  branch: Yup.string(),
+  poll_interval: Yup.number().integer().min(-1, "Use -1 to disable polling"),
+  depth: Yup.number().integer().min(0, "Use 0 to clone the full history"),
+  trusted_keys: Yup.array().when("verify_signatures", {
+    is: true,
+    then: Yup.array().min(1, "Add at least one trusted key"),
+  }),
 });
@@ -1,5 +1,14 @@
 import { deleteRequest, getLocalStorageJWTKeys, parseOrThrowRequest, post, put } from "./utils";
-import { Application, RunStatus, FormData, ApplicationFull, Secret, SecretCreate } from "../types";
+import {
+  Application,
+  RunStatus,
+  FormData,
+  ApplicationFull,
+  LintResult,
+  Secret,
+  SecretCreate,
+  SecretsPreflight,
+} from "../types";
 import { getServerPort } from "./utils";
 import { API_PATH, SERVER_HOSTNAME } from "../constants";
 
@@ -59,9 +68,26 @@ export const fetchApplications = async () => {
   })) as Promise<Application[]>;
 };
 
-export const fetchApplication = async (id: number) => {
+// runQuery selects the overlay, and the branch, tag or commit to read the
+// manifests from instead of the repo branch.
+const runQuery = (overlay?: string, ref?: string) => {
+  const params = new URLSearchParams();
+
+  if (overlay) {
+    params.set("overlay", overlay);
+  }
+
+  if (ref) {
+    params.set("ref", ref);
+  }
+
+  const query = params.toString();
+  return query ? `?${query}` : "";
+};
+
+export const fetchApplication = async (id: number, overlay?: string, ref?: string) => {
   const jwtKeys = getLocalStorageJWTKeys();
-  const url = `${getBaseUrl()}/applications/${id}`;
+  const url = `${getBaseUrl()}/applications/${id}${runQuery(overlay, ref)}`;
   return (await parseOrThrowRequest(url, {
     headers: {
       "Content-Type": "application/json",
@@ -70,6 +96,17 @@ export const fetchApplication = async (id: number) => {
   })) as Promise<ApplicationFull>;
 };
 
+export const lintApplication = async (id: number) => {
+  const jwtKeys = getLocalStorageJWTKeys();
+  const url = `${getBaseUrl()}/applications/${id}/lint`;
+  return (await parseOrThrowRequest(url, {
+    headers: {
+      "Content-Type": "application/json",
+      Authorization: `Bearer ${jwtKeys.token}`,
+    },
+  })) as Promise<LintResult>;
+};
+
 export const fetchApplicationJobs = async (id: number) => {
   const jwtKeys = getLocalStorageJWTKeys();
   const url = `${getBaseUrl()}/applications/${id}/jobs`;
@@ -81,9 +118,9 @@ export const fetchApplicationJobs = async (id: number) => {
   })) as Promise<Application[]>;
 };
 
-export const startJob = async (id: number, data: FormData) => {
+export const startJob = async (id: number, data: FormData, overlay?: string, ref?: string) => {
   const jwtKeys = getLocalStorageJWTKeys();
-  const url = `${getBaseUrl()}/applications/${id}/jobs`;
+  const url = `${getBaseUrl()}/applications/${id}/jobs${runQuery(overlay, ref)}`;
   return (await post(url, data, {
     headers: {
       "Content-Type": "application/json",
@@ -92,6 +129,24 @@ export const startJob = async (id: number, data: FormData) => {
   })) as Promise<RunStatus>;
 };
 
+// preflightSecrets reports the secrets a run of data references and the ones
+// which are not set, without running it.
+export const preflightSecrets = async (
+  id: number,
+  data: FormData,
+  overlay?: string,
+  ref?: string
+) => {
+  const jwtKeys = getLocalStorageJWTKeys();
+  const url = `${getBaseUrl()}/applications/${id}/secrets/preflight${runQuery(overlay, ref)}`;
+  return (await post(url, data, {
+    headers: {
+      "Content-Type": "application/json",
+      Authorization: `Bearer ${jwtKeys.token}`,
+    },
+  })) as Promise<SecretsPreflight>;
+};
+
 export const fetchApplicationSecrets = async (applicationId: string) => {
   const jwtKeys = getLocalStorageJWTKeys();
   const url = `${getBaseUrl()}/applications/${applicationId}/secrets`;
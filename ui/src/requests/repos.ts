@@ -6,8 +6,8 @@ import {
   post,
   put,
 } from "./utils";
-import { Repo, RepoCreate } from "../types";
-import { API_PATH, SERVER_HOSTNAME } from "../constants";
+import { DeployKey, Refs, Repo, RepoCreate, SyncEvent, SyncStarted } from "../types";
+import { API_PATH, SERVER_HOSTNAME, WS_PATH, WS_SECURE } from "../constants";
 
 const DOMAIN = SERVER_HOSTNAME || window.location.hostname;
 const PROTOCOL = window.location.protocol;
@@ -61,6 +61,17 @@ export const fetchRepo = async (id: string) => {
   })) as Promise<Repo>;
 };
 
+export const fetchRepoRefs = async (id: string) => {
+  const jwtKeys = getLocalStorageJWTKeys();
+  const url = `${getBaseUrl()}/repos/${id}/refs`;
+  return (await parseOrThrowRequest(url, {
+    headers: {
+      "Content-Type": "application/json",
+      Authorization: `Bearer ${jwtKeys.token}`,
+    },
+  })) as Promise<Refs>;
+};
+
 export const syncRepo = async (id: string) => {
   const jwtKeys = getLocalStorageJWTKeys();
   const url = `${getBaseUrl()}/repos/${id}/sync`;
@@ -73,12 +84,64 @@ export const syncRepo = async (id: string) => {
         Authorization: `Bearer ${jwtKeys.token}`,
       },
     }
-  )) as Promise<Repo>;
+  )) as Promise<SyncStarted>;
 };
 
-export const deleteRepo = async (id: string) => {
+export const generateDeployKey = async (id: string) => {
   const jwtKeys = getLocalStorageJWTKeys();
-  const url = `${getBaseUrl()}/repos/${id}`;
+  const url = `${getBaseUrl()}/repos/${id}/deploy-key`;
+  return (await post(
+    url,
+    {},
+    {
+      headers: {
+        "Content-Type": "application/json",
+        Authorization: `Bearer ${jwtKeys.token}`,
+      },
+    }
+  )) as Promise<DeployKey>;
+};
+
+export const confirmDeployKey = async (id: string) => {
+  const jwtKeys = getLocalStorageJWTKeys();
+  const url = `${getBaseUrl()}/repos/${id}/deploy-key/confirm`;
+  return (await post(
+    url,
+    {},
+    {
+      headers: {
+        "Content-Type": "application/json",
+        Authorization: `Bearer ${jwtKeys.token}`,
+      },
+    }
+  )) as Promise<DeployKey>;
+};
+
+export const followSync = (syncId: string, onEvent: (event: SyncEvent) => void) => {
+  const protocol = WS_SECURE === "true" ? `wss` : `ws`;
+  const socket = new WebSocket(
+    `${protocol}://${DOMAIN}${PORT ? `:${PORT}` : ""}/${WS_PATH}?id=${syncId}`
+  );
+
+  socket.onopen = () => {
+    socket.send(JSON.stringify({ event: "sync:subscribe" }));
+  };
+
+  socket.onmessage = (message) => {
+    const event = JSON.parse(message.data) as SyncEvent;
+    onEvent(event);
+
+    if (event.phase === "done" || event.phase === "failed") {
+      socket.close();
+    }
+  };
+
+  return socket;
+};
+
+export const deleteRepo = async (id: string, cascade = false) => {
+  const jwtKeys = getLocalStorageJWTKeys();
+  const url = `${getBaseUrl()}/repos/${id}${cascade ? "?cascade=true" : ""}`;
   return (await deleteRequest(
     url,
     {},
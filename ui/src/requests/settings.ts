@@ -1,4 +1,5 @@
-import { getLocalStorageJWTKeys, getServerPort, parseOrThrowRequest } from "./utils";
+import { getLocalStorageJWTKeys, getServerPort, parseOrThrowRequest, post } from "./utils";
+import { Reencrypted } from "../types";
 import { API_PATH, SERVER_HOSTNAME } from "../constants";
 
 const DOMAIN = SERVER_HOSTNAME || window.location.hostname;
@@ -19,3 +20,18 @@ export const fetchSettings = async () => {
     },
   })) as Promise<Record<string, string>>;
 };
+
+export const reencryptSecrets = async () => {
+  const jwtKeys = getLocalStorageJWTKeys();
+  const url = `${getBaseUrl()}/admin/secrets/re-encrypt`;
+  return (await post(
+    url,
+    {},
+    {
+      headers: {
+        "Content-Type": "application/json",
+        Authorization: `Bearer ${jwtKeys.token}`,
+      },
+    }
+  )) as Promise<Reencrypted>;
+};
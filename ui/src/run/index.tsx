@@ -1,10 +1,21 @@
 import { FunctionComponent, ReactElement, useEffect, useState } from "react";
 import { FormData, ApplicationFull } from "../types";
-import { fetchApplication, startJob } from "../requests/applications";
+import { fetchApplication, preflightSecrets, startJob } from "../requests/applications";
 import ApplicationForm from "./ApplicationForm";
 import { useParams } from "react-router-dom";
 import _ from "lodash";
-import { Alert, CircularProgress, Container, styled, useTheme } from "@mui/material";
+import {
+  Alert,
+  CircularProgress,
+  Container,
+  FormControl,
+  FormHelperText,
+  InputLabel,
+  MenuItem,
+  Select,
+  styled,
+  useTheme,
+} from "@mui/material";
 import { useSnackbar } from "notistack";
 import { getErrorMessage } from "../requests/utils";
 import {
@@ -16,6 +27,7 @@ import {
 } from "../components";
 import { Crumbs } from "../Crumbs";
 import { isManifests } from "../utils";
+import { TextField } from "../components/TextField";
 
 const FormContainer = styled(Container)`
   display: flex;
@@ -41,6 +53,11 @@ const Run: FunctionComponent = (): ReactElement => {
   let [loading, setLoading] = useState<boolean>(false);
   const [loadingApp, setLoadingApp] = useState(true);
   const [jobId, setJobId] = useState<number | null>(null);
+  const [overlay, setOverlay] = useState<string>();
+  // ref is applied when the field loses focus, refInput is being edited.
+  const [ref, setRef] = useState<string>("");
+  const [refInput, setRefInput] = useState<string>("");
+  const [missingSecrets, setMissingSecrets] = useState<string[]>([]);
   const { enqueueSnackbar } = useSnackbar();
 
   const handleFormChange = (data: FormData) => {
@@ -65,7 +82,7 @@ const Run: FunctionComponent = (): ReactElement => {
     if (application && cloneData) {
       setLoading(true);
 
-      startJob(application.app.id, cloneData)
+      startJob(application.app.id, cloneData, overlay, ref)
         .then((resp: any) => {
           setJobId(resp.job.id);
           enqueueSnackbar("Job started", {
@@ -88,7 +105,7 @@ const Run: FunctionComponent = (): ReactElement => {
   useEffect(() => {
     if (appId) {
       setLoadingApp(true);
-      fetchApplication(parseInt(appId))
+      fetchApplication(parseInt(appId), overlay, ref)
         .then((data) => {
           if (data?.manifests?.data) {
             setFormData(data.manifests.data);
@@ -101,7 +118,19 @@ const Run: FunctionComponent = (): ReactElement => {
           setLoadingApp(false);
         });
     }
-  }, [appId]);
+  }, [appId, overlay, ref]);
+
+  // Secrets the manifests reference which are not set fail the run, tell
+  // before it is started.
+  useEffect(() => {
+    setMissingSecrets([]);
+
+    if (application && isManifests(application.manifests)) {
+      preflightSecrets(application.app.id, application.manifests.data || {}, overlay, ref)
+        .then((resp) => setMissingSecrets(resp.missing))
+        .catch(() => setMissingSecrets([]));
+    }
+  }, [application, overlay, ref]);
 
   const theme = useTheme();
 
@@ -151,6 +180,52 @@ const Run: FunctionComponent = (): ReactElement => {
 
       {!loadingApp && (
         <FormContainer maxWidth={false}>
+          <FormControl fullWidth={true} sx={{ mb: 2 }}>
+            <TextField
+              fullWidth={true}
+              id="ref"
+              name="ref"
+              label="Branch, tag or commit"
+              placeholder="Repo branch"
+              value={refInput}
+              onChange={(event) => setRefInput(event.target.value)}
+              onBlur={() => setRef(refInput.trim())}
+            />
+
+            {application?.commit && (
+              <FormHelperText id="ref-helper-text">
+                Runs off {application.ref} at {application.commit.substring(0, 7)}, not the
+                repo branch
+              </FormHelperText>
+            )}
+          </FormControl>
+
+          {application?.manifests?.overlays && (
+            <FormControl fullWidth={true} sx={{ mb: 2 }}>
+              <InputLabel id="overlay-label">Overlay</InputLabel>
+              <Select
+                labelId="overlay-label"
+                id="overlay"
+                label="Overlay"
+                value={application.manifests.overlay || ""}
+                onChange={(event) => setOverlay(event.target.value)}
+              >
+                {application.manifests.overlays.map((name) => (
+                  <MenuItem key={name} value={name}>
+                    {name}
+                  </MenuItem>
+                ))}
+              </Select>
+            </FormControl>
+          )}
+
+          {missingSecrets.length > 0 && (
+            <Alert severity="warning" sx={{ mb: 2 }}>
+              Secrets not set: {missingSecrets.join(", ")}. Add them to the application before
+              running it.
+            </Alert>
+          )}
+
           {application && isManifests(application.manifests) ? (
             <ApplicationForm
               defaultData={application.manifests.data}
@@ -1,6 +1,6 @@
 import { FunctionComponent, ReactElement, useEffect, useState } from "react";
-import { fetchSettings } from "../requests/settings";
-import { Typography, styled, Container } from "@mui/material";
+import { fetchSettings, reencryptSecrets } from "../requests/settings";
+import { Button, Typography, styled, Container } from "@mui/material";
 import { getErrorMessage } from "../requests/utils";
 import { useSnackbar } from "notistack";
 
@@ -26,6 +26,36 @@ const Settings: FunctionComponent = (): ReactElement => {
       });
   }, []);
 
+  const [reencrypting, setReencrypting] = useState(false);
+
+  const handleReencrypt = () => {
+    setReencrypting(true);
+    reencryptSecrets()
+      .then((resp) => {
+        enqueueSnackbar(
+          `Re-encrypted ${resp.secrets} secrets and ${resp.webhook_secrets} webhook secrets with key ${resp.key_id}`,
+          { variant: "success" }
+        );
+
+        if (resp.legacy_skipped > 0) {
+          enqueueSnackbar(
+            `${resp.legacy_skipped} values written before key IDs were kept, re-encrypt them through the API with ?legacy=true once SECRETS_KEY is confirmed`,
+            { variant: "warning" }
+          );
+        }
+      })
+      .catch((err) => {
+        err.json().then((resp: any) => {
+          enqueueSnackbar(getErrorMessage(resp), {
+            variant: "error",
+          });
+        });
+      })
+      .finally(() => {
+        setReencrypting(false);
+      });
+  };
+
   return (
     <>
       {settings && (
@@ -41,6 +71,19 @@ const Settings: FunctionComponent = (): ReactElement => {
           <Typography variant="body1" gutterBottom={true}>
             PRIVATE_KEY: {settings.private_key}
           </Typography>
+
+          <Typography variant="body1" gutterBottom={true}>
+            SECRETS_KEY_ID: {settings.secrets_key_id || "none"}
+          </Typography>
+
+          <Button
+            variant="outlined"
+            size="small"
+            disabled={reencrypting || !settings.secrets_key_id}
+            onClick={handleReencrypt}
+          >
+            Re-encrypt secrets with the current key
+          </Button>
         </StyledContainer>
       )}
     </>
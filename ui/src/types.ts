@@ -6,24 +6,73 @@ type Schema = {
 
 export type FormData = Record<string, any>;
 
+export type StatusMatch = {
+  field: string;
+  values?: string[];
+};
+
+export type Workload = {
+  apiVersion: string;
+  kind: string;
+  status: {
+    running?: StatusMatch;
+    succeeded?: StatusMatch;
+    failed?: StatusMatch;
+  };
+  podTemplatePath?: string;
+};
+
 export type Application = {
   id: number;
   name: string;
   repo_id: string;
   branch: string;
   manifest_path: any;
+  manifest_type: "static" | "helm" | "kustomize" | "template";
+  chart_path: string;
+  workload?: Workload | null;
+  git_owned?: boolean;
+  concurrency?: number;
+  schedules?: string[] | null;
+  required_secrets?: string[] | null;
+  notifications?: NotificationTarget[] | null;
   created_at: string;
   updated_at: string;
   deleted_at: string;
 };
 
+export type NotificationTarget = {
+  type: "webhook" | "slack" | "email";
+  target: string;
+  phases?: string[];
+};
+
 export type ApplicationFull = {
   app: Application;
   manifests: {
     data: FormData;
     schema: RJSFSchema;
     ui_schema: Schema;
+    type: "static" | "helm" | "kustomize" | "template";
+    overlays?: string[];
+    overlay?: string;
+    template?: string;
   };
+  manifests_error?: string;
+  ref?: string;
+  commit?: string;
+};
+
+export type ManifestIssue = {
+  file: string;
+  line?: number;
+  severity: "error" | "warning";
+  message: string;
+};
+
+export type LintResult = {
+  valid: boolean;
+  issues: ManifestIssue[] | null;
 };
 
 export type Repo = {
@@ -32,12 +81,77 @@ export type Repo = {
   branch: string;
   commit: string;
   hash: string;
+  poll_interval: number;
+  depth: number;
+  submodules: boolean;
+  sparse: boolean;
+  lfs: boolean;
+  verify_signatures: boolean;
+  trusted_keys: string[];
+  policy_failure?: string;
+  config_errors?: string[] | null;
+  discovery_errors?: string[] | null;
+  deploy_key?: string;
+  pending_deploy_key?: string;
+};
+
+export type Ref = {
+  name: string;
+  hash: string;
+};
+
+export type Refs = {
+  default_branch: string;
+  branches: Ref[];
+  tags: Ref[];
+};
+
+export type DeployKey = {
+  deploy_key: string;
+  pending_deploy_key: string;
+};
+
+export type SecretsPreflight = {
+  referenced: string[];
+  set: string[];
+  missing: string[];
+};
+
+export type Reencrypted = {
+  key_id: string;
+  secrets: number;
+  webhook_secrets: number;
+  legacy_rewritten: number;
+  legacy_skipped: number;
+};
+
+export type SyncStarted = {
+  sync_id: string;
+  message?: string;
+};
+
+export type SyncEvent = {
+  sync_id: string;
+  repo_id: number;
+  phase?: string;
+  message?: string;
+  hash?: string;
+  commit?: string;
+  error?: string;
 };
 
 export type RepoCreate = {
   url: string;
   branch: string;
   ssh_private_key: string;
+  webhook_secret: string;
+  poll_interval: number;
+  depth: number;
+  submodules: boolean;
+  sparse: boolean;
+  lfs: boolean;
+  verify_signatures: boolean;
+  trusted_keys: string[];
 };
 
 export type Secret = {